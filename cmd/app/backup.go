@@ -0,0 +1,59 @@
+package main
+
+import (
+	"AvitoTestTask/internal/domain"
+	backupuc "AvitoTestTask/internal/usecases/backup"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func runExportCommand(ctx context.Context, svc backupuc.Service, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	out := fs.String("o", "", "output file (stdout by default)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	snap, err := svc.Export(ctx)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snap)
+}
+
+func runRestoreCommand(ctx context.Context, svc backupuc.Service, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: restore <file>")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	var snap domain.Snapshot
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&snap); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if err := svc.Restore(ctx, &snap); err != nil {
+		return err
+	}
+	fmt.Printf("restored %d teams, %d users, %d repositories, %d pull requests\n", len(snap.Teams), len(snap.Users), len(snap.Repositories), len(snap.PullRequests))
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"AvitoTestTask/internal/domain"
+	importuc "AvitoTestTask/internal/usecases/bulkimport"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runImportCommand(ctx context.Context, svc importuc.Service, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "file format (csv|json|yaml); detected from the extension by default")
+	dryRun := fs.Bool("dry-run", false, "validate and report without committing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: import [-format csv|json|yaml] [-dry-run] <file>")
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = importuc.FormatFromName(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	report, err := svc.Import(ctx, *format, f, *dryRun)
+	if report != nil {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ROW\tTEAM\tUSER\tSTATUS\tERROR")
+		for _, row := range report.Rows {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", row.Row, row.TeamName, row.UserID, row.Status, row.Error)
+		}
+		if ferr := tw.Flush(); ferr != nil {
+			return ferr
+		}
+		fmt.Printf("teams created: %d, dry run: %t, applied: %t\n", report.TeamsCreated, report.DryRun, report.Applied)
+	}
+	if errors.Is(err, domain.ErrImportInvalid) {
+		return fmt.Errorf("%w; nothing was imported", err)
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"AvitoTestTask/internal/domain"
+	authuc "AvitoTestTask/internal/usecases/auth"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runTokenCommand(ctx context.Context, svc authuc.Service, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: token create|list|revoke [flags]")
+	}
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+		name := fs.String("name", "", "token name")
+		role := fs.String("role", string(domain.RoleUser), "token role (admin|user)")
+		userID := fs.String("user", "", "user id the token acts as")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		var uid *string
+		if *userID != "" {
+			uid = userID
+		}
+		raw, t, err := svc.IssueToken(ctx, *name, domain.Role(*role), uid)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("token_id: %s\nrole: %s\ntoken: %s\n", t.ID, t.Role, raw)
+		return nil
+	case "list":
+		tokens, err := svc.ListTokens(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tROLE\tUSER\tREVOKED")
+		for _, t := range tokens {
+			user := "-"
+			if t.UserID != nil {
+				user = *t.UserID
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", t.ID, t.Name, t.Role, user, t.RevokedAt != nil)
+		}
+		return tw.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New("usage: token revoke <token_id>")
+		}
+		return svc.RevokeToken(ctx, args[1])
+	default:
+		return fmt.Errorf("unknown token command %q", args[0])
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"net/http"
+	"strings"
+)
+
+type principalKey struct{}
+
+func principalFrom(ctx context.Context) *domain.APIToken {
+	t, _ := ctx.Value(principalKey{}).(*domain.APIToken)
+	return t
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := r.Header.Get("Authorization")
+		raw, ok := strings.CutPrefix(h, "Bearer ")
+		if !ok || raw == "" {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", domain.ErrUnauthorized.Error())
+			return
+		}
+		t, err := s.authSvc.Authenticate(r.Context(), strings.TrimSpace(raw))
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", domain.ErrUnauthorized.Error())
+			return
+		}
+		ctx := context.WithValue(r.Context(), principalKey{}, t)
+		ctx = withLogAttrs(ctx, "token_id", t.ID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t := principalFrom(r.Context()); t == nil || !t.IsAdmin() {
+			writeError(w, http.StatusForbidden, "FORBIDDEN", domain.ErrForbidden.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func canManagePR(t *domain.APIToken, pr *domain.PullRequest) bool {
+	if t == nil {
+		return false
+	}
+	if t.IsAdmin() {
+		return true
+	}
+	return t.UserID != nil && *t.UserID == pr.AuthorID
+}
+
+func canActAs(t *domain.APIToken, userID string) bool {
+	if t == nil {
+		return false
+	}
+	return t.IsAdmin() || (t.UserID != nil && *t.UserID == userID)
+}
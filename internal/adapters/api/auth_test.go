@@ -0,0 +1,21 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCreatePRForAnotherAuthorForbidden(t *testing.T) {
+	srv := NewServer(fakeAuth{}, nil, nil, nil, nil, WithLogger(slog.New(slog.DiscardHandler)))
+	body := `{"pull_request_id":"` + testPRID + `","pull_request_name":"feature","author_id":"` + testAuthorID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer reviewer-token")
+	rec := httptest.NewRecorder()
+	srv.r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	backupuc "AvitoTestTask/internal/usecases/backup"
+)
+
+const (
+	maxRestoreBody = 256 << 20
+	backupTimeout  = 10 * time.Minute
+)
+
+func WithBackupService(svc backupuc.Service) Option {
+	return func(s *Server) {
+		s.backupSvc = svc
+	}
+}
+
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	extendDeadlines(w, backupTimeout)
+	snap, err := s.backupSvc.Export(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="export-`+snap.ExportedAt.Format("20060102T150405Z")+`.json"`)
+	writeJSON(w, http.StatusOK, snap)
+}
+
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	extendDeadlines(w, backupTimeout)
+	var snap domain.Snapshot
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRestoreBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&snap); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid snapshot: "+err.Error())
+		return
+	}
+	if err := s.backupSvc.Restore(r.Context(), &snap); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRestoreNotEmpty):
+			writeError(w, http.StatusConflict, "NOT_EMPTY", err.Error())
+		case errors.Is(err, domain.ErrSnapshotVersion):
+			writeError(w, http.StatusBadRequest, "UNSUPPORTED_VERSION", err.Error())
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "RESTORE_FAILED", err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{
+		"teams":         len(snap.Teams),
+		"users":         len(snap.Users),
+		"repositories":  len(snap.Repositories),
+		"pull_requests": len(snap.PullRequests),
+	})
+}
+
+// extendDeadlines lifts the server-wide read/write timeouts for long transfers.
+func extendDeadlines(w http.ResponseWriter, d time.Duration) {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(d)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
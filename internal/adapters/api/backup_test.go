@@ -0,0 +1,47 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	backupuc "AvitoTestTask/internal/usecases/backup"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type slowBackup struct {
+	backupuc.Service
+	delay time.Duration
+}
+
+func (b slowBackup) Export(context.Context) (*domain.Snapshot, error) {
+	time.Sleep(b.delay)
+	return &domain.Snapshot{FormatVersion: domain.SnapshotFormatVersion, ExportedAt: time.Now().UTC()}, nil
+}
+
+func TestExportOutlivesServerWriteTimeout(t *testing.T) {
+	srv := NewServer(fakeAuth{}, nil, nil, nil, nil,
+		WithBackupService(slowBackup{delay: 200 * time.Millisecond}), WithLogger(slog.New(slog.DiscardHandler)))
+	ts := httptest.NewUnstartedServer(srv.r)
+	ts.Config.WriteTimeout = 50 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/export", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("export cut off by the write timeout: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var snap domain.Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, decode err %v", resp.StatusCode, err)
+	}
+	if snap.FormatVersion != domain.SnapshotFormatVersion {
+		t.Errorf("format_version = %d", snap.FormatVersion)
+	}
+}
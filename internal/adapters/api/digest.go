@@ -0,0 +1,83 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	digestuc "AvitoTestTask/internal/usecases/digest"
+)
+
+func WithDigestService(svc digestuc.Service) Option {
+	return func(s *Server) {
+		s.digestSvc = svc
+	}
+}
+
+func (s *Server) handleDigestGet(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if !canActAs(principalFrom(r.Context()), userID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the user or an admin can view digest settings")
+		return
+	}
+	sub, err := s.digestSvc.GetSubscription(r.Context(), userID)
+	if err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toDigestResponse(sub))
+}
+
+func (s *Server) handleDigestSet(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if !canActAs(principalFrom(r.Context()), userID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the user or an admin can change digest settings")
+		return
+	}
+	var req DigestSubscriptionRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	sub := domain.EmailSubscription{UserID: userID, Email: req.Email, Enabled: true}
+	if req.Enabled != nil {
+		sub.Enabled = *req.Enabled
+	}
+	if err := s.digestSvc.Subscribe(r.Context(), sub); err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	out, err := s.digestSvc.GetSubscription(r.Context(), userID)
+	if err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toDigestResponse(out))
+}
+
+func (s *Server) handleDigestDelete(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if !canActAs(principalFrom(r.Context()), userID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the user or an admin can change digest settings")
+		return
+	}
+	if err := s.digestSvc.Unsubscribe(r.Context(), userID); err != nil {
+		writeDigestError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func toDigestResponse(sub *domain.EmailSubscription) DigestSubscriptionResponse {
+	return DigestSubscriptionResponse{UserID: sub.UserID, Email: sub.Email, Enabled: sub.Enabled, LastSentAt: sub.LastSentAt}
+}
+
+func writeDigestError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrSubscriptionNotFound) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+}
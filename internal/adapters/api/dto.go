@@ -1,14 +1,47 @@
 package api
 
+import "time"
+
 type TeamAddRequest struct {
 	TeamName string   `json:"team_name"`
 	Users    []string `json:"users"`
 }
 
+type TeamAddResponse struct {
+	TeamID   string `json:"team_id"`
+	TeamName string `json:"team_name"`
+}
+
+type TeamMemberRequest struct {
+	UserID   string  `json:"user_id"`
+	Role     *string `json:"role,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+type TeamMemberUpdateRequest struct {
+	Role     *string `json:"role,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+type TeamMemberResponse struct {
+	UserID           string `json:"user_id"`
+	Username         string `json:"username"`
+	IsActive         bool   `json:"is_active"`
+	Role             string `json:"role"`
+	MembershipActive bool   `json:"membership_active"`
+}
+
+type TeamResponse struct {
+	TeamID   string               `json:"team_id"`
+	TeamName string               `json:"team_name"`
+	Members  []TeamMemberResponse `json:"members"`
+}
+
 type CreateUserRequest struct {
 	UserID   string  `json:"user_id"`
 	Username string  `json:"username"`
 	TeamID   *string `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
 	IsActive *bool   `json:"is_active,omitempty"`
 }
 
@@ -16,45 +49,161 @@ type UpdateUserRequest struct {
 	UserID   string  `json:"user_id"`
 	Username *string `json:"username,omitempty"`
 	TeamID   *string `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
 	IsActive *bool   `json:"is_active,omitempty"`
 }
 
+type UserResponse struct {
+	UserID   string  `json:"user_id"`
+	Username string  `json:"username"`
+	TeamID   *string `json:"team_id,omitempty"`
+	TeamName *string `json:"team_name,omitempty"`
+	IsActive bool    `json:"is_active"`
+}
+
 type GetUserResponse struct {
-	User struct {
-		UserID   string  `json:"user_id"`
-		Username string  `json:"username"`
-		TeamID   *string `json:"team_id,omitempty"`
-		IsActive bool    `json:"is_active"`
-	} `json:"user"`
+	User UserResponse `json:"user"`
 }
 
-type PullRequestCreateRequest struct {
+type ReviewHandoffResponse struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	OldReviewerID   string `json:"old_reviewer_id"`
+	NewReviewerID   string `json:"new_reviewer_id,omitempty"`
+}
+
+type UserUpdateResponse struct {
+	UserResponse
+	ReassignedReviews []ReviewHandoffResponse `json:"reassigned_reviews"`
+}
+
+type UserDeleteResponse struct {
+	Deleted           bool                    `json:"deleted"`
+	ReassignedReviews []ReviewHandoffResponse `json:"reassigned_reviews"`
+}
+
+type PullRequestCreateRequest struct {
+	PullRequestID   string  `json:"pull_request_id"`
+	PullRequestName string  `json:"pull_request_name"`
+	AuthorID        string  `json:"author_id"`
+	TargetTeam      *string `json:"target_team,omitempty"`
+	Repository      *string `json:"repository,omitempty"`
 }
 
 type PullRequestReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id,omitempty"`
+	Version       *int   `json:"version,omitempty"`
+}
+
+type PullRequestReviewerAddRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Force         bool   `json:"force,omitempty"`
+	Version       *int   `json:"version,omitempty"`
+}
+
+type PullRequestReviewerRemoveRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Version       *int   `json:"version,omitempty"`
 }
 
 type PullRequestMergeRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Version       *int   `json:"version,omitempty"`
 }
 
 type PullRequestResponse struct {
-	PullRequestID   string   `json:"pull_request_id"`
-	PullRequestName string   `json:"pull_request_name"`
-	AuthorID        string   `json:"author_id"`
-	Status          string   `json:"status"`
-	Reviewers       []string `json:"reviewers"`
+	PullRequestID   string               `json:"pull_request_id"`
+	PullRequestName string               `json:"pull_request_name"`
+	AuthorID        string               `json:"author_id"`
+	TeamID          string               `json:"team_id,omitempty"`
+	RepositoryID    string               `json:"repository_id,omitempty"`
+	Status          string               `json:"status"`
+	Reviewers       []string             `json:"reviewers"`
+	External        *ExternalRefResponse `json:"external,omitempty"`
+	Version         int                  `json:"version"`
 }
 
 type ReviewerPullRequestsResponse struct {
 	PullRequests []PullRequestResponse `json:"pull_requests"`
 }
 
+type RepositoryCreateRequest struct {
+	Name             string  `json:"name"`
+	VCSURL           string  `json:"vcs_url"`
+	OwningTeam       *string `json:"owning_team,omitempty"`
+	ReviewerCount    *int    `json:"reviewer_count,omitempty"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty"`
+}
+
+type RepositoryUpdateRequest struct {
+	Name             string  `json:"name"`
+	NewName          *string `json:"new_name,omitempty"`
+	VCSURL           *string `json:"vcs_url,omitempty"`
+	OwningTeam       *string `json:"owning_team,omitempty"`
+	ReviewerCount    *int    `json:"reviewer_count,omitempty"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty"`
+}
+
+type RepositoryResponse struct {
+	RepositoryID     string  `json:"repository_id"`
+	Name             string  `json:"name"`
+	VCSURL           string  `json:"vcs_url"`
+	OwningTeamID     *string `json:"owning_team_id,omitempty"`
+	OwningTeam       *string `json:"owning_team,omitempty"`
+	ReviewerCount    int     `json:"reviewer_count"`
+	ReviewerStrategy string  `json:"reviewer_strategy"`
+}
+
+type RepositoryListResponse struct {
+	Repositories []RepositoryResponse `json:"repositories"`
+}
+
+type ExternalRefResponse struct {
+	Provider string `json:"provider"`
+	Repo     string `json:"repo"`
+	Number   int    `json:"number"`
+}
+
+type IdentityLinkRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+type IdentityListResponse struct {
+	Identities []IdentityLinkRequest `json:"identities"`
+}
+
+type WebhookResponse struct {
+	Result string               `json:"result"`
+	PR     *PullRequestResponse `json:"pr,omitempty"`
+}
+
+type TeamNotificationsRequest struct {
+	WebhookURL string  `json:"webhook_url"`
+	Mode       *string `json:"mode,omitempty"`
+}
+
+type UserNotificationsRequest struct {
+	OptOut bool `json:"opt_out"`
+}
+
+type DigestSubscriptionRequest struct {
+	Email   string `json:"email"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+type DigestSubscriptionResponse struct {
+	UserID     string     `json:"user_id"`
+	Email      string     `json:"email"`
+	Enabled    bool       `json:"enabled"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
 type ErrorObject struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
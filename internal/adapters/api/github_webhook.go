@@ -0,0 +1,130 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	vcsuc "AvitoTestTask/internal/usecases/vcsevent"
+)
+
+const maxWebhookBody = 1 << 20
+
+type githubPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func WithGitHubWebhook(secret string, svc vcsuc.Service) Option {
+	return func(s *Server) {
+		s.githubSecret = secret
+		s.vcsEventSvc = svc
+	}
+}
+
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request body")
+		return
+	}
+	if !validGitHubSignature(s.githubSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid webhook signature")
+		return
+	}
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		writeJSON(w, http.StatusOK, WebhookResponse{Result: vcsuc.ResultIgnored})
+		return
+	case "pull_request":
+	default:
+		writeJSON(w, http.StatusAccepted, WebhookResponse{Result: vcsuc.ResultIgnored})
+		return
+	}
+	var p githubPullRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil || p.Repository.FullName == "" || p.Number <= 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid pull_request payload")
+		return
+	}
+	ev := domain.PullRequestEvent{
+		Ref:         domain.ExternalRef{Provider: domain.ProviderGitHub, Repo: p.Repository.FullName, Number: p.Number},
+		Title:       p.PullRequest.Title,
+		AuthorLogin: p.PullRequest.User.Login,
+		Draft:       p.PullRequest.Draft,
+	}
+	switch p.Action {
+	case "opened", "ready_for_review":
+		ev.Action = domain.PREventOpened
+	case "reopened":
+		ev.Action = domain.PREventReopened
+	case "closed":
+		ev.Action = domain.PREventClosed
+		if p.PullRequest.Merged {
+			ev.Action = domain.PREventMerged
+		}
+	default:
+		writeJSON(w, http.StatusAccepted, WebhookResponse{Result: vcsuc.ResultIgnored})
+		return
+	}
+	s.dispatchPullRequestEvent(w, r, ev)
+}
+
+func (s *Server) dispatchPullRequestEvent(w http.ResponseWriter, r *http.Request, ev domain.PullRequestEvent) {
+	res, err := s.vcsEventSvc.HandlePullRequestEvent(r.Context(), ev)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownIdentity) && ev.AuthorLogin == "":
+			writeError(w, http.StatusUnprocessableEntity, "UNKNOWN_IDENTITY", "event was sent by someone other than the author; the author's login is unknown")
+		case errors.Is(err, domain.ErrUnknownIdentity):
+			writeError(w, http.StatusUnprocessableEntity, "UNKNOWN_IDENTITY", "no user linked to "+ev.Ref.Provider+" login "+ev.AuthorLogin)
+		case errors.Is(err, domain.ErrNoTeam), errors.Is(err, domain.ErrTeamNotFound):
+			writeError(w, http.StatusUnprocessableEntity, "NO_TEAM", err.Error())
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeConflict(w, err)
+		case errors.Is(err, domain.ErrPRMerged):
+			writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+		default:
+			logging.FromContext(r.Context()).Error("handle pull request event", "provider", ev.Ref.Provider, "repo", ev.Ref.Repo, "number", ev.Ref.Number, "err", err)
+			writeError(w, http.StatusInternalServerError, "INTERNAL", "internal error")
+		}
+		return
+	}
+	resp := WebhookResponse{Result: res.Action}
+	if res.PR != nil {
+		pr := toPRResponse(res.PR)
+		resp.PR = &pr
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok || secret == "" {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
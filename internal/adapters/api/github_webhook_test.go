@@ -0,0 +1,182 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	vcsuc "AvitoTestTask/internal/usecases/vcsevent"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testGitHubSecret = "gh-secret"
+	testGitLabToken  = "gl-token"
+)
+
+type recordingEvents struct {
+	events []domain.PullRequestEvent
+}
+
+func (r *recordingEvents) HandlePullRequestEvent(_ context.Context, ev domain.PullRequestEvent) (*vcsuc.Result, error) {
+	switch ev.AuthorLogin {
+	case "ghost-user":
+		return nil, domain.ErrUnknownIdentity
+	case "broken-user":
+		return nil, errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+	}
+	r.events = append(r.events, ev)
+	return &vcsuc.Result{Action: string(ev.Action)}, nil
+}
+
+func newWebhookServer(t *testing.T) (*Server, *recordingEvents) {
+	t.Helper()
+	events := &recordingEvents{}
+	srv := NewServer(nil, nil, nil, nil, nil,
+		WithLogger(slog.New(slog.DiscardHandler)),
+		WithGitHubWebhook(testGitHubSecret, events),
+		WithGitLabWebhook(testGitLabToken, events),
+	)
+	return srv, events
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postGitHub(srv *Server, event, signature string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", bytes.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	rec := httptest.NewRecorder()
+	srv.r.ServeHTTP(rec, req)
+	return rec
+}
+
+func webhookResult(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp WebhookResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode %s: %v", rec.Body, err)
+	}
+	return resp.Result
+}
+
+func TestGitHubWebhookRejectsBadSignature(t *testing.T) {
+	srv, events := newWebhookServer(t)
+	body := fixture(t, "github_pull_request_opened.json")
+	for name, sig := range map[string]string{
+		"missing":    "",
+		"wrong key":  sign("other-secret", body),
+		"not hex":    "sha256=zz",
+		"wrong algo": "sha1=" + sign(testGitHubSecret, body)[len("sha256="):],
+		"other body": sign(testGitHubSecret, append(body, ' ')),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if rec := postGitHub(srv, "pull_request", sig, body); rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want 401", rec.Code)
+			}
+		})
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("events dispatched despite bad signature: %v", events.events)
+	}
+}
+
+func TestGitHubWebhookMapsActions(t *testing.T) {
+	tests := []struct {
+		fixture string
+		status  int
+		action  domain.PREventAction
+	}{
+		{"github_pull_request_opened.json", http.StatusOK, domain.PREventOpened},
+		{"github_pull_request_closed.json", http.StatusOK, domain.PREventClosed},
+		{"github_pull_request_merged.json", http.StatusOK, domain.PREventMerged},
+		{"github_pull_request_synchronize.json", http.StatusAccepted, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			srv, events := newWebhookServer(t)
+			body := fixture(t, tt.fixture)
+			rec := postGitHub(srv, "pull_request", sign(testGitHubSecret, body), body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.action == "" {
+				if len(events.events) != 0 || webhookResult(t, rec) != vcsuc.ResultIgnored {
+					t.Fatalf("expected ignored event, got %v", events.events)
+				}
+				return
+			}
+			if len(events.events) != 1 {
+				t.Fatalf("dispatched %d events, want 1", len(events.events))
+			}
+			ev := events.events[0]
+			want := domain.ExternalRef{Provider: domain.ProviderGitHub, Repo: "acme/widgets", Number: 42}
+			if ev.Action != tt.action || ev.Ref != want || ev.AuthorLogin != "octocat" || ev.Title != "Add widget caching" {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+		})
+	}
+}
+
+func TestGitHubWebhookUnknownIdentity(t *testing.T) {
+	srv, _ := newWebhookServer(t)
+	body := fixture(t, "github_pull_request_unknown_author.json")
+	rec := postGitHub(srv, "pull_request", sign(testGitHubSecret, body), body)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Error.Code != "UNKNOWN_IDENTITY" {
+		t.Fatalf("unexpected body %s", rec.Body)
+	}
+}
+
+func TestGitHubWebhookHidesInternalErrors(t *testing.T) {
+	srv, _ := newWebhookServer(t)
+	body := bytes.ReplaceAll(fixture(t, "github_pull_request_opened.json"), []byte("octocat"), []byte("broken-user"))
+	rec := postGitHub(srv, "pull_request", sign(testGitHubSecret, body), body)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if bytes.Contains(rec.Body.Bytes(), []byte("10.0.0.5")) {
+		t.Fatalf("internal error leaked: %s", rec.Body)
+	}
+}
+
+func TestGitHubWebhookIgnoresOtherEvents(t *testing.T) {
+	srv, events := newWebhookServer(t)
+	body := []byte(`{"zen":"Keep it logically awesome."}`)
+	if rec := postGitHub(srv, "ping", sign(testGitHubSecret, body), body); rec.Code != http.StatusOK {
+		t.Fatalf("ping status = %d", rec.Code)
+	}
+	if rec := postGitHub(srv, "push", sign(testGitHubSecret, body), body); rec.Code != http.StatusAccepted {
+		t.Fatalf("push status = %d", rec.Code)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("unexpected events %v", events.events)
+	}
+}
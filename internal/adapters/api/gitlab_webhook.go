@@ -0,0 +1,94 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	vcsuc "AvitoTestTask/internal/usecases/vcsevent"
+)
+
+type gitlabMergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		AuthorID       int    `json:"author_id"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+func WithGitLabWebhook(token string, svc vcsuc.Service) Option {
+	return func(s *Server) {
+		s.gitlabToken = token
+		s.vcsEventSvc = svc
+	}
+}
+
+func (s *Server) handleGitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Gitlab-Token")
+	if s.gitlabToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.gitlabToken)) != 1 {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid webhook token")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request body")
+		return
+	}
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		writeJSON(w, http.StatusAccepted, WebhookResponse{Result: vcsuc.ResultIgnored})
+		return
+	}
+	var p gitlabMergeRequestPayload
+	if err := json.Unmarshal(body, &p); err != nil || p.ObjectKind != "merge_request" || p.Project.PathWithNamespace == "" || p.ObjectAttributes.IID <= 0 {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid merge_request payload")
+		return
+	}
+	attrs := p.ObjectAttributes
+	ev := domain.PullRequestEvent{
+		Ref:   domain.ExternalRef{Provider: domain.ProviderGitLab, Repo: p.Project.PathWithNamespace, Number: attrs.IID},
+		Title: attrs.Title,
+		Draft: attrs.Draft || attrs.WorkInProgress,
+	}
+	if p.User.ID == attrs.AuthorID {
+		ev.AuthorLogin = p.User.Username
+	}
+	switch attrs.Action {
+	case "open":
+		ev.Action = domain.PREventOpened
+	case "reopen":
+		ev.Action = domain.PREventReopened
+	case "close":
+		ev.Action = domain.PREventClosed
+	case "merge":
+		ev.Action = domain.PREventMerged
+	case "update":
+		if d := p.Changes.Draft; d == nil || !d.Previous || d.Current {
+			writeJSON(w, http.StatusAccepted, WebhookResponse{Result: vcsuc.ResultIgnored})
+			return
+		}
+		ev.Action = domain.PREventOpened
+	default:
+		writeJSON(w, http.StatusAccepted, WebhookResponse{Result: vcsuc.ResultIgnored})
+		return
+	}
+	s.dispatchPullRequestEvent(w, r, ev)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	vcsuc "AvitoTestTask/internal/usecases/vcsevent"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func postGitLab(srv *Server, token string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", bytes.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	if token != "" {
+		req.Header.Set("X-Gitlab-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	srv.r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGitLabWebhookRejectsBadToken(t *testing.T) {
+	srv, events := newWebhookServer(t)
+	body := fixture(t, "gitlab_merge_request_open.json")
+	for _, token := range []string{"", "wrong", testGitLabToken + "x"} {
+		if rec := postGitLab(srv, token, body); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("token %q: status = %d, want 401", token, rec.Code)
+		}
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("events dispatched despite bad token: %v", events.events)
+	}
+}
+
+func TestGitLabWebhookMapsActions(t *testing.T) {
+	tests := []struct {
+		fixture string
+		status  int
+		action  domain.PREventAction
+	}{
+		{"gitlab_merge_request_open.json", http.StatusOK, domain.PREventOpened},
+		{"gitlab_merge_request_close.json", http.StatusOK, domain.PREventClosed},
+		{"gitlab_merge_request_merge.json", http.StatusOK, domain.PREventMerged},
+		{"gitlab_merge_request_ready.json", http.StatusOK, domain.PREventOpened},
+		{"gitlab_merge_request_update.json", http.StatusAccepted, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			srv, events := newWebhookServer(t)
+			rec := postGitLab(srv, testGitLabToken, fixture(t, tt.fixture))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.action == "" {
+				if len(events.events) != 0 || webhookResult(t, rec) != vcsuc.ResultIgnored {
+					t.Fatalf("expected ignored event, got %v", events.events)
+				}
+				return
+			}
+			if len(events.events) != 1 {
+				t.Fatalf("dispatched %d events, want 1", len(events.events))
+			}
+			ev := events.events[0]
+			want := domain.ExternalRef{Provider: domain.ProviderGitLab, Repo: "platform/billing", Number: 7}
+			if ev.Action != tt.action || ev.Ref != want || ev.AuthorLogin != "jdoe" || ev.Title != "Retry failed invoices" {
+				t.Fatalf("unexpected event %+v", ev)
+			}
+		})
+	}
+}
+
+func TestGitLabWebhookIgnoresNonAuthorActor(t *testing.T) {
+	for _, name := range []string{"gitlab_merge_request_ready_by_maintainer.json", "gitlab_merge_request_reopen_by_maintainer.json"} {
+		t.Run(name, func(t *testing.T) {
+			srv, events := newWebhookServer(t)
+			rec := postGitLab(srv, testGitLabToken, fixture(t, name))
+			if rec.Code != http.StatusOK || len(events.events) != 1 {
+				t.Fatalf("status = %d, events = %v", rec.Code, events.events)
+			}
+			if login := events.events[0].AuthorLogin; login != "" {
+				t.Fatalf("author attributed to actor %q", login)
+			}
+		})
+	}
+}
+
+func TestGitLabWebhookUnknownIdentity(t *testing.T) {
+	srv, _ := newWebhookServer(t)
+	rec := postGitLab(srv, testGitLabToken, fixture(t, "gitlab_merge_request_unknown_author.json"))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", rec.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type ReadinessCheck func(ctx context.Context) error
+
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func (s *Server) BeginShutdown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: "ready", Checks: make(map[string]string, len(s.checks)+1)}
+	code := http.StatusOK
+	if s.shuttingDown.Load() {
+		resp.Checks["shutdown"] = "shutting down"
+		code = http.StatusServiceUnavailable
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+	for name, check := range s.checks {
+		if err := check(ctx); err != nil {
+			resp.Checks[name] = err.Error()
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+	if code != http.StatusOK {
+		resp.Status = "not ready"
+	}
+	writeJSON(w, code, resp)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	identityuc "AvitoTestTask/internal/usecases/identity"
+)
+
+func WithIdentityService(svc identityuc.Service) Option {
+	return func(s *Server) {
+		s.identitySvc = svc
+	}
+}
+
+func (s *Server) handleIdentityLink(w http.ResponseWriter, r *http.Request) {
+	var req IdentityLinkRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	id := domain.ExternalIdentity{Provider: req.Provider, Login: req.Login, UserID: req.UserID}
+	if err := s.identitySvc.Link(r.Context(), id); err != nil {
+		writeError(w, http.StatusBadRequest, "IDENTITY_LINK_FAILED", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, req)
+}
+
+func (s *Server) handleIdentityUnlink(w http.ResponseWriter, r *http.Request) {
+	err := s.identitySvc.Unlink(r.Context(), chi.URLParam(r, "provider"), chi.URLParam(r, "login"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownIdentity) {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func (s *Server) handleIdentityList(w http.ResponseWriter, r *http.Request) {
+	ids, err := s.identitySvc.ListForUser(r.Context(), chi.URLParam(r, "user_id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	out := IdentityListResponse{Identities: make([]IdentityLinkRequest, 0, len(ids))}
+	for _, id := range ids {
+		out.Identities = append(out.Identities, IdentityLinkRequest{Provider: id.Provider, Login: id.Login, UserID: id.UserID})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	importuc "AvitoTestTask/internal/usecases/bulkimport"
+)
+
+const maxImportBody = 10 << 20
+
+func WithImportService(svc importuc.Service) Option {
+	return func(s *Server) {
+		s.importSvc = svc
+	}
+}
+
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = formatFromContentType(r.Header.Get("Content-Type"))
+	}
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid dry_run")
+			return
+		}
+		dryRun = b
+	}
+	report, err := s.importSvc.Import(r.Context(), format, http.MaxBytesReader(w, r.Body, maxImportBody), dryRun)
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, report)
+	case report != nil && errors.Is(err, domain.ErrImportInvalid):
+		writeJSON(w, http.StatusUnprocessableEntity, report)
+	case report != nil:
+		writeJSON(w, http.StatusConflict, report)
+	default:
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+	}
+}
+
+func formatFromContentType(ct string) string {
+	mt, _, _ := mime.ParseMediaType(ct)
+	switch mt {
+	case "text/csv":
+		return importuc.FormatCSV
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return importuc.FormatYAML
+	}
+	return importuc.FormatJSON
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type accessLogKey struct{}
+
+type accessLog struct {
+	attrs []any
+}
+
+func withLogAttrs(ctx context.Context, args ...any) context.Context {
+	if a, ok := ctx.Value(accessLogKey{}).(*accessLog); ok {
+		a.attrs = append(a.attrs, args...)
+	}
+	return logging.WithLogger(ctx, logging.FromContext(ctx).With(args...))
+}
+
+func (s *Server) requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := r.Header.Get(requestIDHeader)
+		if reqID == "" || len(reqID) > 128 {
+			reqID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, reqID)
+		l := s.logger.With("request_id", reqID)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		a := &accessLog{}
+		ctx := context.WithValue(logging.WithLogger(r.Context(), l), accessLogKey{}, a)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+		l.With(a.attrs...).Info("http request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", ww.Status(),
+			"bytes", ww.BytesWritten(),
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+		)
+	})
+}
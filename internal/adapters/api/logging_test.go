@@ -0,0 +1,42 @@
+package api
+
+import (
+	"AvitoTestTask/internal/infra/tracing"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAccessLogCarriesTraceAndToken(t *testing.T) {
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(tracetest.NewInMemoryExporter())))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	var buf bytes.Buffer
+	srv := NewServer(fakeAuth{}, nil, nil, nil, nil, WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader("{"))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	srv.r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err == nil && e["msg"] == "http request" {
+			entry = e
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log line in %s", buf.String())
+	}
+	if entry["token_id"] != "44444444-4444-4444-4444-444444444444" || entry["trace_id"] == nil {
+		t.Errorf("access log = %v", entry)
+	}
+}
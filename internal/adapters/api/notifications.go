@@ -0,0 +1,69 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	notifyuc "AvitoTestTask/internal/usecases/notify"
+)
+
+func WithNotificationService(svc notifyuc.Service) Option {
+	return func(s *Server) {
+		s.notifySvc = svc
+	}
+}
+
+func (s *Server) handleTeamNotificationsSet(w http.ResponseWriter, r *http.Request) {
+	var req TeamNotificationsRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	mode := domain.ChatImmediate
+	if req.Mode != nil {
+		mode = domain.ChatMode(*req.Mode)
+	}
+	if err := s.notifySvc.ConfigureTeam(r.Context(), chi.URLParam(r, "team_name"), req.WebhookURL, mode); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, TeamNotificationsRequest{WebhookURL: req.WebhookURL, Mode: (*string)(&mode)})
+}
+
+func (s *Server) handleTeamNotificationsDelete(w http.ResponseWriter, r *http.Request) {
+	if err := s.notifySvc.DisableTeam(r.Context(), chi.URLParam(r, "team_name")); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func (s *Server) handleUserNotificationsSet(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if !canActAs(principalFrom(r.Context()), userID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the user or an admin can change notification settings")
+		return
+	}
+	var req UserNotificationsRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	if err := s.notifySvc.SetOptOut(r.Context(), userID, req.OptOut); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, req)
+}
+
+func writeNotificationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTeamNotFound), errors.Is(err, domain.ErrChatNotConfigured):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) handleRepositoryCreate(w http.ResponseWriter, r *http.Request) {
+	var req RepositoryCreateRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	cr := domain.CodeRepository{
+		Name:           req.Name,
+		VCSURL:         req.VCSURL,
+		OwningTeamName: req.OwningTeam,
+		Policy:         domain.DefaultReviewerPolicy(),
+	}
+	if req.ReviewerCount != nil {
+		cr.Policy.ReviewerCount = *req.ReviewerCount
+	}
+	if req.ReviewerStrategy != nil {
+		cr.Policy.Strategy = domain.ReviewerStrategy(*req.ReviewerStrategy)
+	}
+	out, err := s.repoSvc.CreateRepository(r.Context(), cr)
+	if err != nil {
+		writeRepositoryError(w, "REPOSITORY_CREATE_FAILED", err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toRepositoryResponse(out))
+}
+
+func (s *Server) handleRepositoryGet(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repoSvc.GetRepository(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toRepositoryResponse(out))
+}
+
+func (s *Server) handleRepositoryList(w http.ResponseWriter, r *http.Request) {
+	repos, err := s.repoSvc.ListRepositories(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	out := RepositoryListResponse{Repositories: make([]RepositoryResponse, 0, len(repos))}
+	for i := range repos {
+		out.Repositories = append(out.Repositories, toRepositoryResponse(&repos[i]))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleRepositoryUpdate(w http.ResponseWriter, r *http.Request) {
+	var req RepositoryUpdateRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	existing, err := s.repoSvc.GetRepository(r.Context(), req.Name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	if req.NewName != nil {
+		existing.Name = *req.NewName
+	}
+	if req.VCSURL != nil {
+		existing.VCSURL = *req.VCSURL
+	}
+	if req.OwningTeam != nil {
+		existing.OwningTeamName = req.OwningTeam
+		if *req.OwningTeam == "" {
+			existing.OwningTeamName = nil
+		}
+	}
+	if req.ReviewerCount != nil {
+		existing.Policy.ReviewerCount = *req.ReviewerCount
+	}
+	if req.ReviewerStrategy != nil {
+		existing.Policy.Strategy = domain.ReviewerStrategy(*req.ReviewerStrategy)
+	}
+	out, err := s.repoSvc.UpdateRepository(r.Context(), req.Name, *existing)
+	if err != nil {
+		writeRepositoryError(w, "REPOSITORY_UPDATE_FAILED", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toRepositoryResponse(out))
+}
+
+func (s *Server) handleRepositoryDelete(w http.ResponseWriter, r *http.Request) {
+	if err := s.repoSvc.DeleteRepository(r.Context(), chi.URLParam(r, "name")); err != nil {
+		writeRepositoryError(w, "REPOSITORY_DELETE_FAILED", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func writeRepositoryError(w http.ResponseWriter, code string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRepositoryNotFound), errors.Is(err, domain.ErrTeamNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, code, err.Error())
+	}
+}
+
+func toRepositoryResponse(cr *domain.CodeRepository) RepositoryResponse {
+	return RepositoryResponse{
+		RepositoryID:     cr.ID,
+		Name:             cr.Name,
+		VCSURL:           cr.VCSURL,
+		OwningTeamID:     cr.OwningTeamID,
+		OwningTeam:       cr.OwningTeamName,
+		ReviewerCount:    cr.Policy.ReviewerCount,
+		ReviewerStrategy: string(cr.Policy.Strategy),
+	}
+}
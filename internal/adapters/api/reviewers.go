@@ -0,0 +1,115 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	pruc "AvitoTestTask/internal/usecases/pullrequest"
+	"errors"
+	"net/http"
+)
+
+func (s *Server) handlePRReviewerAdd(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReviewerAddRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	version, err := expectedVersion(r, req.Version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	t := principalFrom(r.Context())
+	if !s.authorizeReviewerChange(w, r, req.PullRequestID) {
+		return
+	}
+	if req.Force && !t.IsAdmin() {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only an admin can exceed the reviewer limit")
+		return
+	}
+	pr, err := s.prSvc.AddReviewer(r.Context(), pruc.ReviewerChangeInput{
+		PRID:            req.PullRequestID,
+		UserID:          req.UserID,
+		Force:           req.Force,
+		ActorTokenID:    tokenID(t),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		writeReviewerChangeError(w, err)
+		return
+	}
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, toPRResponse(pr))
+}
+
+func (s *Server) handlePRReviewerRemove(w http.ResponseWriter, r *http.Request) {
+	var req PullRequestReviewerRemoveRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	version, err := expectedVersion(r, req.Version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	if !s.authorizeReviewerChange(w, r, req.PullRequestID) {
+		return
+	}
+	pr, err := s.prSvc.RemoveReviewer(r.Context(), pruc.ReviewerChangeInput{
+		PRID:            req.PullRequestID,
+		UserID:          req.UserID,
+		ActorTokenID:    tokenID(principalFrom(r.Context())),
+		ExpectedVersion: version,
+	})
+	if err != nil {
+		writeReviewerChangeError(w, err)
+		return
+	}
+	setETag(w, pr)
+	writeJSON(w, http.StatusOK, toPRResponse(pr))
+}
+
+func (s *Server) authorizeReviewerChange(w http.ResponseWriter, r *http.Request, prID string) bool {
+	pr, err := s.prSvc.GetPR(r.Context(), prID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return false
+	}
+	if !canManagePR(principalFrom(r.Context()), pr) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the pr author or an admin can change reviewers")
+		return false
+	}
+	return true
+}
+
+func writeReviewerChangeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeConflict(w, err)
+	case errors.Is(err, domain.ErrPRMerged):
+		writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+	case errors.Is(err, domain.ErrPRClosed):
+		writeError(w, http.StatusConflict, "PR_CLOSED", err.Error())
+	case errors.Is(err, domain.ErrNotMember):
+		writeError(w, http.StatusConflict, "NOT_MEMBER", err.Error())
+	case errors.Is(err, domain.ErrReviewerInactive):
+		writeError(w, http.StatusConflict, "REVIEWER_INACTIVE", err.Error())
+	case errors.Is(err, domain.ErrAuthorReviewer):
+		writeError(w, http.StatusConflict, "AUTHOR_REVIEWER", err.Error())
+	case errors.Is(err, domain.ErrAlreadyAssigned):
+		writeError(w, http.StatusConflict, "ALREADY_ASSIGNED", err.Error())
+	case errors.Is(err, domain.ErrReviewerLimit):
+		writeError(w, http.StatusConflict, "REVIEWER_LIMIT", err.Error())
+	case errors.Is(err, domain.ErrReviewerNotAssigned):
+		writeError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	}
+}
+
+func tokenID(t *domain.APIToken) string {
+	if t == nil {
+		return ""
+	}
+	return t.ID
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	pruc "AvitoTestTask/internal/usecases/pullrequest"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type recordingPRs struct {
+	pruc.Service
+	changes []pruc.ReviewerChangeInput
+}
+
+func (p *recordingPRs) GetPR(_ context.Context, id string) (*domain.PullRequest, error) {
+	return &domain.PullRequest{ID: id, AuthorID: testAuthorID, Status: domain.StatusOpen}, nil
+}
+
+func (p *recordingPRs) AddReviewer(_ context.Context, in pruc.ReviewerChangeInput) (*domain.PullRequest, error) {
+	p.changes = append(p.changes, in)
+	return p.GetPR(context.Background(), in.PRID)
+}
+
+func (p *recordingPRs) RemoveReviewer(_ context.Context, in pruc.ReviewerChangeInput) (*domain.PullRequest, error) {
+	p.changes = append(p.changes, in)
+	return p.GetPR(context.Background(), in.PRID)
+}
+
+func TestReviewerChangesRequireAuthorOrAdmin(t *testing.T) {
+	body := `{"pull_request_id":"` + testPRID + `","user_id":"` + testReviewerID + `"}`
+	tests := []struct {
+		path   string
+		token  string
+		status int
+	}{
+		{"/pullRequest/reviewers/add", "reviewer-token", http.StatusForbidden},
+		{"/pullRequest/reviewers/remove", "reviewer-token", http.StatusForbidden},
+		{"/pullRequest/reviewers/add", "admin-token", http.StatusOK},
+		{"/pullRequest/reviewers/remove", "admin-token", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.token, func(t *testing.T) {
+			prs := &recordingPRs{}
+			srv := NewServer(fakeAuth{}, nil, nil, prs, nil, WithLogger(slog.New(slog.DiscardHandler)))
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			srv.r.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if applied := len(prs.changes) > 0; applied != (tt.status == http.StatusOK) {
+				t.Fatalf("change applied = %v", applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	scimUserSchema  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimBasePath    = "/scim/v2"
+	maxSCIMBody     = 1 << 20
+)
+
+var (
+	scimEqFilter     = regexp.MustCompile(`^\s*(\w+)\s+eq\s+"([^"]*)"\s*$`)
+	scimMemberFilter = regexp.MustCompile(`^members\[value eq "([^"]+)"\]$`)
+)
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+type scimUser struct {
+	Schemas    []string  `json:"schemas"`
+	ID         string    `json:"id,omitempty"`
+	ExternalID string    `json:"externalId,omitempty"`
+	UserName   string    `json:"userName"`
+	Active     *bool     `json:"active,omitempty"`
+	Meta       *scimMeta `json:"meta,omitempty"`
+}
+
+type scimMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []scimMember `json:"members"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type scimPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type scimPatchRequest struct {
+	Schemas    []string      `json:"schemas"`
+	Operations []scimPatchOp `json:"Operations"`
+}
+
+func (s *Server) scimRoutes(r chi.Router) {
+	r.Get("/Users", s.handleSCIMUserList)
+	r.Post("/Users", s.handleSCIMUserCreate)
+	r.Get("/Users/{id}", s.handleSCIMUserGet)
+	r.Put("/Users/{id}", s.handleSCIMUserReplace)
+	r.Patch("/Users/{id}", s.handleSCIMUserPatch)
+	r.Delete("/Users/{id}", s.handleSCIMUserDelete)
+	r.Get("/Groups", s.handleSCIMGroupList)
+	r.Post("/Groups", s.handleSCIMGroupCreate)
+	r.Get("/Groups/{id}", s.handleSCIMGroupGet)
+	r.Put("/Groups/{id}", s.handleSCIMGroupReplace)
+	r.Patch("/Groups/{id}", s.handleSCIMGroupPatch)
+	r.Delete("/Groups/{id}", s.handleSCIMGroupDelete)
+}
+
+func (s *Server) handleSCIMUserList(w http.ResponseWriter, r *http.Request) {
+	username, ok := scimFilter(r, "userName")
+	if !ok {
+		writeSCIMError(w, http.StatusBadRequest, "invalidFilter", "only 'userName eq \"...\"' filters are supported")
+		return
+	}
+	users, err := s.userSvc.ListUsers(r.Context(), username)
+	if err != nil {
+		writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	resources := make([]interface{}, 0, len(users))
+	for i := range users {
+		resources = append(resources, toSCIMUser(&users[i]))
+	}
+	writeSCIMList(w, r, resources)
+}
+
+func (s *Server) handleSCIMUserGet(w http.ResponseWriter, r *http.Request) {
+	u, err := s.userSvc.GetUser(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "user not found")
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(u))
+}
+
+func (s *Server) handleSCIMUserCreate(w http.ResponseWriter, r *http.Request) {
+	var req scimUser
+	if err := decodeSCIM(w, r, &req); err != nil || strings.TrimSpace(req.UserName) == "" {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", "userName is required")
+		return
+	}
+	existing, err := s.userSvc.ListUsers(r.Context(), req.UserName)
+	if err != nil {
+		writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	if len(existing) > 0 {
+		writeSCIMError(w, http.StatusConflict, "uniqueness", "userName already exists")
+		return
+	}
+	u := domain.User{ID: uuid.NewString(), Username: req.UserName, IsActive: req.Active == nil || *req.Active}
+	if err := s.userSvc.CreateUser(r.Context(), u); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	w.Header().Set("Location", scimBasePath+"/Users/"+u.ID)
+	writeSCIM(w, http.StatusCreated, toSCIMUser(&u))
+}
+
+func (s *Server) handleSCIMUserReplace(w http.ResponseWriter, r *http.Request) {
+	var req scimUser
+	if err := decodeSCIM(w, r, &req); err != nil || strings.TrimSpace(req.UserName) == "" {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", "userName is required")
+		return
+	}
+	s.updateSCIMUser(w, r, func(u *domain.User) error {
+		u.Username = req.UserName
+		u.IsActive = req.Active == nil || *req.Active
+		return nil
+	})
+}
+
+func (s *Server) handleSCIMUserPatch(w http.ResponseWriter, r *http.Request) {
+	var req scimPatchRequest
+	if err := decodeSCIM(w, r, &req); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "invalid PatchOp")
+		return
+	}
+	s.updateSCIMUser(w, r, func(u *domain.User) error {
+		for _, op := range req.Operations {
+			if !strings.EqualFold(op.Op, "replace") && !strings.EqualFold(op.Op, "add") {
+				return errors.New("unsupported op " + op.Op)
+			}
+			attrs := map[string]json.RawMessage{}
+			if op.Path == "" {
+				if err := json.Unmarshal(op.Value, &attrs); err != nil {
+					return errors.New("value must be an object when path is omitted")
+				}
+			} else {
+				attrs[op.Path] = op.Value
+			}
+			for path, raw := range attrs {
+				switch path {
+				case "active":
+					active, err := scimBool(raw)
+					if err != nil {
+						return err
+					}
+					u.IsActive = active
+				case "userName":
+					if err := json.Unmarshal(raw, &u.Username); err != nil || u.Username == "" {
+						return errors.New("invalid userName")
+					}
+				default:
+					return errors.New("unsupported path " + path)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Server) updateSCIMUser(w http.ResponseWriter, r *http.Request, apply func(*domain.User) error) {
+	u, err := s.userSvc.GetUser(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "user not found")
+		return
+	}
+	if err := apply(u); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	_, err = s.userSvc.UpdateUser(r.Context(), *u)
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeSCIMError(w, http.StatusConflict, "", err.Error())
+		return
+	case err != nil:
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMUser(u))
+}
+
+func (s *Server) handleSCIMUserDelete(w http.ResponseWriter, r *http.Request) {
+	if _, err := s.userSvc.DeleteUser(r.Context(), chi.URLParam(r, "id")); err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "user not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSCIMGroupList(w http.ResponseWriter, r *http.Request) {
+	name, ok := scimFilter(r, "displayName")
+	if !ok {
+		writeSCIMError(w, http.StatusBadRequest, "invalidFilter", "only 'displayName eq \"...\"' filters are supported")
+		return
+	}
+	var teams []domain.Team
+	if name != "" {
+		team, err := s.teamSvc.GetTeamByName(r.Context(), name)
+		if err == nil {
+			teams = append(teams, *team)
+		} else if !errors.Is(err, domain.ErrTeamNotFound) {
+			writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+	} else {
+		var err error
+		if teams, err = s.teamSvc.ListTeams(r.Context()); err != nil {
+			writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+			return
+		}
+	}
+	resources := make([]interface{}, 0, len(teams))
+	for i := range teams {
+		resources = append(resources, toSCIMGroup(&teams[i]))
+	}
+	writeSCIMList(w, r, resources)
+}
+
+func (s *Server) handleSCIMGroupGet(w http.ResponseWriter, r *http.Request) {
+	team, err := s.teamSvc.GetTeamByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "group not found")
+		return
+	}
+	writeSCIM(w, http.StatusOK, toSCIMGroup(team))
+}
+
+func (s *Server) handleSCIMGroupCreate(w http.ResponseWriter, r *http.Request) {
+	var req scimGroup
+	if err := decodeSCIM(w, r, &req); err != nil || strings.TrimSpace(req.DisplayName) == "" {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", "displayName is required")
+		return
+	}
+	teamID, err := s.teamSvc.CreateTeamWithMembers(r.Context(), req.DisplayName, scimMemberIDs(req.Members))
+	if errors.Is(err, domain.ErrTeamNameTaken) {
+		writeSCIMError(w, http.StatusConflict, "uniqueness", "displayName already exists")
+		return
+	}
+	if err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return
+	}
+	w.Header().Set("Location", scimBasePath+"/Groups/"+teamID)
+	s.writeSCIMGroup(w, r, teamID, http.StatusCreated)
+}
+
+func (s *Server) handleSCIMGroupReplace(w http.ResponseWriter, r *http.Request) {
+	var req scimGroup
+	if err := decodeSCIM(w, r, &req); err != nil || strings.TrimSpace(req.DisplayName) == "" {
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", "displayName is required")
+		return
+	}
+	s.replaceSCIMGroup(w, r, chi.URLParam(r, "id"), req.DisplayName, scimMemberIDs(req.Members))
+}
+
+func (s *Server) handleSCIMGroupPatch(w http.ResponseWriter, r *http.Request) {
+	var req scimPatchRequest
+	if err := decodeSCIM(w, r, &req); err != nil {
+		writeSCIMError(w, http.StatusBadRequest, "invalidSyntax", "invalid PatchOp")
+		return
+	}
+	team, err := s.teamSvc.GetTeamByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "group not found")
+		return
+	}
+	name := team.TeamName
+	var replace []string
+	var add, remove []string
+	for _, op := range req.Operations {
+		switch {
+		case strings.EqualFold(op.Op, "replace") && op.Path == "displayName":
+			if err := json.Unmarshal(op.Value, &name); err != nil || name == "" {
+				writeSCIMError(w, http.StatusBadRequest, "invalidValue", "invalid displayName")
+				return
+			}
+		case strings.EqualFold(op.Op, "replace") && op.Path == "members":
+			var ms []scimMember
+			if err := json.Unmarshal(op.Value, &ms); err != nil {
+				writeSCIMError(w, http.StatusBadRequest, "invalidValue", "invalid members")
+				return
+			}
+			replace = []string{}
+			for _, m := range ms {
+				replace = append(replace, m.Value)
+			}
+		case strings.EqualFold(op.Op, "add") && op.Path == "members":
+			var ms []scimMember
+			if err := json.Unmarshal(op.Value, &ms); err != nil {
+				writeSCIMError(w, http.StatusBadRequest, "invalidValue", "invalid members")
+				return
+			}
+			for _, m := range ms {
+				add = append(add, m.Value)
+			}
+		case strings.EqualFold(op.Op, "remove") && op.Path == "members":
+			var ms []scimMember
+			if err := json.Unmarshal(op.Value, &ms); err != nil {
+				writeSCIMError(w, http.StatusBadRequest, "invalidValue", "invalid members")
+				return
+			}
+			for _, m := range ms {
+				remove = append(remove, m.Value)
+			}
+		case strings.EqualFold(op.Op, "remove") && scimMemberFilter.MatchString(op.Path):
+			remove = append(remove, scimMemberFilter.FindStringSubmatch(op.Path)[1])
+		default:
+			writeSCIMError(w, http.StatusBadRequest, "invalidPath", "unsupported operation "+op.Op+" "+op.Path)
+			return
+		}
+	}
+	members := replace
+	if members == nil {
+		members = make([]string, 0, len(team.Members))
+		for _, m := range team.Members {
+			members = append(members, m.UserID)
+		}
+	}
+	members = slices.DeleteFunc(append(members, add...), func(uid string) bool { return slices.Contains(remove, uid) })
+	s.replaceSCIMGroup(w, r, team.ID, name, members)
+}
+
+func (s *Server) replaceSCIMGroup(w http.ResponseWriter, r *http.Request, teamID, name string, members []string) {
+	err := s.teamSvc.ReplaceTeam(r.Context(), teamID, name, members)
+	switch {
+	case errors.Is(err, domain.ErrTeamNotFound), errors.Is(err, domain.ErrInvalidID):
+		writeSCIMError(w, http.StatusNotFound, "", "group not found")
+	case errors.Is(err, domain.ErrTeamNameTaken):
+		writeSCIMError(w, http.StatusConflict, "uniqueness", "displayName already exists")
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeSCIMError(w, http.StatusConflict, "", err.Error())
+	case err != nil:
+		writeSCIMError(w, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		s.writeSCIMGroup(w, r, teamID, http.StatusOK)
+	}
+}
+
+func (s *Server) handleSCIMGroupDelete(w http.ResponseWriter, r *http.Request) {
+	team, err := s.teamSvc.GetTeamByID(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		writeSCIMError(w, http.StatusNotFound, "", "group not found")
+		return
+	}
+	switch err := s.teamSvc.DeleteTeam(r.Context(), team.TeamName); {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeSCIMError(w, http.StatusConflict, "", err.Error())
+		return
+	case err != nil:
+		writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeSCIMGroup(w http.ResponseWriter, r *http.Request, teamID string, code int) {
+	team, err := s.teamSvc.GetTeamByID(r.Context(), teamID)
+	if err != nil {
+		writeSCIMError(w, http.StatusInternalServerError, "", err.Error())
+		return
+	}
+	writeSCIM(w, code, toSCIMGroup(team))
+}
+
+func scimMemberIDs(ms []scimMember) []string {
+	out := make([]string, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, m.Value)
+	}
+	return out
+}
+
+func toSCIMUser(u *domain.User) scimUser {
+	active := u.IsActive
+	return scimUser{
+		Schemas:  []string{scimUserSchema},
+		ID:       u.ID,
+		UserName: u.Username,
+		Active:   &active,
+		Meta:     &scimMeta{ResourceType: "User", Location: scimBasePath + "/Users/" + u.ID},
+	}
+}
+
+func toSCIMGroup(t *domain.Team) scimGroup {
+	g := scimGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          t.ID,
+		DisplayName: t.TeamName,
+		Members:     make([]scimMember, 0, len(t.Members)),
+		Meta:        &scimMeta{ResourceType: "Group", Location: scimBasePath + "/Groups/" + t.ID},
+	}
+	for _, m := range t.Members {
+		g.Members = append(g.Members, scimMember{Value: m.UserID, Display: m.Username})
+	}
+	return g
+}
+
+func decodeSCIM(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSCIMBody)).Decode(v)
+}
+
+func scimFilter(r *http.Request, attr string) (string, bool) {
+	f := r.URL.Query().Get("filter")
+	if f == "" {
+		return "", true
+	}
+	m := scimEqFilter.FindStringSubmatch(f)
+	if m == nil || !strings.EqualFold(m[1], attr) {
+		return "", false
+	}
+	return m[2], true
+}
+
+func scimBool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if v, err := strconv.ParseBool(s); err == nil {
+			return v, nil
+		}
+	}
+	return false, errors.New("invalid boolean")
+}
+
+func writeSCIMList(w http.ResponseWriter, r *http.Request, resources []interface{}) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if start < 1 {
+		start = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 0 {
+		count = len(resources)
+	}
+	total := len(resources)
+	page := []interface{}{}
+	if start <= total {
+		end := min(start-1+count, total)
+		page = resources[start-1 : end]
+	}
+	writeSCIM(w, http.StatusOK, scimListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: total,
+		StartIndex:   start,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	})
+}
+
+func writeSCIM(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeSCIMError(w http.ResponseWriter, code int, scimType, detail string) {
+	body := map[string]interface{}{
+		"schemas": []string{scimErrorSchema},
+		"status":  strconv.Itoa(code),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		body["scimType"] = scimType
+	}
+	writeSCIM(w, code, body)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	teamuc "AvitoTestTask/internal/usecases/team"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	scimMemberA = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	scimMemberB = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+	scimMemberC = "cccccccc-cccc-cccc-cccc-cccccccccccc"
+)
+
+type replaceCall struct {
+	teamID, name string
+	members      []string
+}
+
+type recordingTeams struct {
+	teamuc.Service
+	replaced []replaceCall
+}
+
+func (t *recordingTeams) GetTeamByID(_ context.Context, id string) (*domain.Team, error) {
+	if id != testTeamID {
+		return nil, domain.ErrTeamNotFound
+	}
+	return &domain.Team{ID: id, TeamName: "backend", Members: []domain.TeamMember{{UserID: scimMemberA}, {UserID: scimMemberB}}}, nil
+}
+
+func (t *recordingTeams) ReplaceTeam(_ context.Context, teamID, name string, members []string) error {
+	t.replaced = append(t.replaced, replaceCall{teamID, name, members})
+	return nil
+}
+
+func scimRequest(t *testing.T, teams teamuc.Service, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	srv := NewServer(fakeAuth{}, teams, nil, nil, nil, WithLogger(slog.New(slog.DiscardHandler)))
+	req := httptest.NewRequest(method, scimBasePath+path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	srv.r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSCIMGroupPatchAppliesOnce(t *testing.T) {
+	teams := &recordingTeams{}
+	body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[
+		{"op":"replace","path":"displayName","value":"platform"},
+		{"op":"add","path":"members","value":[{"value":"` + scimMemberC + `"}]},
+		{"op":"remove","path":"members[value eq \"` + scimMemberA + `\"]"}]}`
+	rec := scimRequest(t, teams, http.MethodPatch, "/Groups/"+testTeamID, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if len(teams.replaced) != 1 {
+		t.Fatalf("ReplaceTeam called %d times, want 1", len(teams.replaced))
+	}
+	got := teams.replaced[0]
+	if got.teamID != testTeamID || got.name != "platform" || !slices.Equal(got.members, []string{scimMemberB, scimMemberC}) {
+		t.Errorf("ReplaceTeam(%q, %q, %v)", got.teamID, got.name, got.members)
+	}
+}
+
+func TestSCIMRejectsOversizedBody(t *testing.T) {
+	teams := &recordingTeams{}
+	body := `{"displayName":"backend","members":[` + strings.Repeat(`{"value":"`+scimMemberA+`"},`, maxSCIMBody/40) + `{}]}`
+	rec := scimRequest(t, teams, http.MethodPut, "/Groups/"+testTeamID, body)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if len(teams.replaced) != 0 {
+		t.Fatal("oversized body was applied")
+	}
+}
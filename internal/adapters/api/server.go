@@ -2,45 +2,194 @@ package api
 
 import (
 	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	authuc "AvitoTestTask/internal/usecases/auth"
+	backupuc "AvitoTestTask/internal/usecases/backup"
+	importuc "AvitoTestTask/internal/usecases/bulkimport"
+	repouc "AvitoTestTask/internal/usecases/coderepo"
+	digestuc "AvitoTestTask/internal/usecases/digest"
+	identityuc "AvitoTestTask/internal/usecases/identity"
+	notifyuc "AvitoTestTask/internal/usecases/notify"
 	pruc "AvitoTestTask/internal/usecases/pullrequest"
 	teamuc "AvitoTestTask/internal/usecases/team"
 	useruc "AvitoTestTask/internal/usecases/user"
+	vcsuc "AvitoTestTask/internal/usecases/vcsevent"
 )
 
 type Server struct {
+	authSvc authuc.Service
 	teamSvc teamuc.Service
 	userSvc useruc.Service
 	prSvc   pruc.Service
+	repoSvc repouc.Service
+
+	identitySvc  identityuc.Service
+	notifySvc    notifyuc.Service
+	digestSvc    digestuc.Service
+	importSvc    importuc.Service
+	backupSvc    backupuc.Service
+	vcsEventSvc  vcsuc.Service
+	githubSecret string
+	gitlabToken  string
+
+	limiter RateLimitStore
+	limits  map[string]RateLimit
+	logger  *slog.Logger
+	metrics HTTPMetrics
+	checks  map[string]ReadinessCheck
+
+	shuttingDown atomic.Bool
 
 	r   *chi.Mux
 	srv *http.Server
 }
 
-func NewServer(teamSvc teamuc.Service, userSvc useruc.Service, prSvc pruc.Service) *Server {
+type Option func(*Server)
+
+func WithMetrics(m HTTPMetrics) Option {
+	return func(s *Server) {
+		s.metrics = m
+	}
+}
+
+func WithReadinessChecks(checks map[string]ReadinessCheck) Option {
+	return func(s *Server) {
+		s.checks = checks
+	}
+}
+
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+func WithRateLimiter(store RateLimitStore, limits map[string]RateLimit) Option {
+	return func(s *Server) {
+		s.limiter = store
+		s.limits = limits
+	}
+}
+
+func NewServer(authSvc authuc.Service, teamSvc teamuc.Service, userSvc useruc.Service, prSvc pruc.Service, repoSvc repouc.Service, opts ...Option) *Server {
 	r := chi.NewRouter()
-	s := &Server{teamSvc: teamSvc, userSvc: userSvc, prSvc: prSvc, r: r}
-	r.Post("/team/add", s.handleTeamAdd)
-	r.Post("/pullRequest/create", s.handlePRCreate)
-	r.Post("/pullRequest/reassign", s.handlePRReassign)
-	r.Post("/pullRequest/merge", s.handlePRMerge)
-	r.Get("/reviewer/{reviewer_id}/pullRequests", s.handleReviewerPRs)
-
-	r.Post("/user/create", s.handleUserCreate)
-	r.Get("/user/{user_id}", s.handleUserGet)
-	r.Put("/user/update", s.handleUserUpdate)
-	r.Delete("/user/{user_id}", s.handleUserDelete)
-	r.Put("/team/update", s.handleTeamUpdate)
-	r.Delete("/team/{team_name}", s.handleTeamDelete)
+	s := &Server{authSvc: authSvc, teamSvc: teamSvc, userSvc: userSvc, prSvc: prSvc, repoSvc: repoSvc, r: r, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	r.Use(s.requestLogger)
+	r.Use(s.traceRequest)
+	if s.metrics != nil {
+		r.Use(s.instrument)
+		r.Method(http.MethodGet, "/metrics", s.metrics.Handler())
+	}
+	r.Get("/healthz", s.handleHealthz)
+	r.Get("/readyz", s.handleReadyz)
+	if s.vcsEventSvc != nil {
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(s.rateLimit("webhook"))
+			if s.githubSecret != "" {
+				r.Post("/github", s.handleGitHubWebhook)
+			}
+			if s.gitlabToken != "" {
+				r.Post("/gitlab", s.handleGitLabWebhook)
+			}
+		})
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(s.rateLimit("ip"))
+		r.Use(s.authenticate)
+
+		r.Route("/pullRequest", func(r chi.Router) {
+			r.Use(s.rateLimit("pullRequest"))
+			r.Post("/create", s.handlePRCreate)
+			r.Post("/reassign", s.handlePRReassign)
+			r.Post("/reviewers/add", s.handlePRReviewerAdd)
+			r.Post("/reviewers/remove", s.handlePRReviewerRemove)
+			r.Post("/merge", s.handlePRMerge)
+		})
+		r.Route("/reviewer", func(r chi.Router) {
+			r.Use(s.rateLimit("reviewer"))
+			r.Get("/{reviewer_id}/pullRequests", s.handleReviewerPRs)
+		})
+		r.Route("/user", func(r chi.Router) {
+			r.Use(s.rateLimit("user"))
+			r.With(requireAdmin).Post("/create", s.handleUserCreate)
+			r.Get("/{user_id}", s.handleUserGet)
+			r.Put("/update", s.handleUserUpdate)
+			r.With(requireAdmin).Delete("/{user_id}", s.handleUserDelete)
+			r.With(requireAdmin).Post("/{user_id}/restore", s.handleUserRestore)
+			if s.notifySvc != nil {
+				r.Put("/{user_id}/notifications", s.handleUserNotificationsSet)
+			}
+			if s.digestSvc != nil {
+				r.Get("/{user_id}/digest", s.handleDigestGet)
+				r.Put("/{user_id}/digest", s.handleDigestSet)
+				r.Delete("/{user_id}/digest", s.handleDigestDelete)
+			}
+		})
+		r.Route("/repository", func(r chi.Router) {
+			r.Use(s.rateLimit("repository"))
+			r.Get("/list", s.handleRepositoryList)
+			r.Get("/{name}", s.handleRepositoryGet)
+			r.Group(func(r chi.Router) {
+				r.Use(requireAdmin)
+				r.Post("/create", s.handleRepositoryCreate)
+				r.Put("/update", s.handleRepositoryUpdate)
+				r.Delete("/{name}", s.handleRepositoryDelete)
+			})
+		})
+		if s.identitySvc != nil {
+			r.Route("/identity", func(r chi.Router) {
+				r.Use(requireAdmin)
+				r.Use(s.rateLimit("user"))
+				r.Post("/link", s.handleIdentityLink)
+				r.Get("/{user_id}", s.handleIdentityList)
+				r.Delete("/{provider}/{login}", s.handleIdentityUnlink)
+			})
+		}
+		if s.importSvc != nil {
+			r.With(requireAdmin, s.rateLimit("team")).Post("/import", s.handleImport)
+		}
+		r.Route(scimBasePath, func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Use(s.rateLimit("scim"))
+			s.scimRoutes(r)
+		})
+		if s.backupSvc != nil {
+			r.With(requireAdmin).Get("/export", s.handleExport)
+			r.With(requireAdmin).Post("/restore", s.handleRestore)
+		}
+		r.Route("/team", func(r chi.Router) {
+			r.Use(requireAdmin)
+			r.Use(s.rateLimit("team"))
+			r.Post("/add", s.handleTeamAdd)
+			r.Put("/update", s.handleTeamUpdate)
+			r.Delete("/{team_name}", s.handleTeamDelete)
+			r.Post("/{team_name}/restore", s.handleTeamRestore)
+			r.Get("/{team_name}/members", s.handleTeamMembers)
+			r.Post("/{team_name}/members", s.handleTeamMemberAdd)
+			r.Put("/{team_name}/members/{user_id}", s.handleTeamMemberUpdate)
+			r.Delete("/{team_name}/members/{user_id}", s.handleTeamMemberRemove)
+			if s.notifySvc != nil {
+				r.Put("/{team_name}/notifications", s.handleTeamNotificationsSet)
+				r.Delete("/{team_name}/notifications", s.handleTeamNotificationsDelete)
+			}
+		})
+	})
 
 	s.srv = &http.Server{
 		Handler:      r,
@@ -66,7 +215,7 @@ func (s *Server) Shutdown(ctx context.Context) error {
 func decodeStrict(r *http.Request, v interface{}) error {
 	defer func() {
 		if err := r.Body.Close(); err != nil {
-			log.Printf("failed to close request body: %v", err)
+			logging.FromContext(r.Context()).Warn("failed to close request body", "err", err)
 		}
 	}()
 	dec := json.NewDecoder(r.Body)
@@ -84,30 +233,89 @@ func writeError(w http.ResponseWriter, httpCode int, code, message string) {
 	writeJSON(w, httpCode, ErrorResponse{Error: ErrorObject{Code: code, Message: message}})
 }
 
+func writeConflict(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", "1")
+	writeError(w, http.StatusConflict, "VERSION_CONFLICT", err.Error()+"; reload the pull request and retry")
+}
+
+func toPRResponse(pr *domain.PullRequest) PullRequestResponse {
+	return PullRequestResponse{
+		PullRequestID:   pr.ID,
+		PullRequestName: pr.Name,
+		AuthorID:        pr.AuthorID,
+		TeamID:          pr.TeamID,
+		RepositoryID:    pr.RepositoryID,
+		Status:          string(pr.Status),
+		Reviewers:       pr.AssignedReviewers,
+		External:        toExternalRefResponse(pr.External),
+		Version:         pr.Version,
+	}
+}
+
+func toExternalRefResponse(ref *domain.ExternalRef) *ExternalRefResponse {
+	if ref == nil {
+		return nil
+	}
+	return &ExternalRefResponse{Provider: ref.Provider, Repo: ref.Repo, Number: ref.Number}
+}
+
+func toUserResponse(u *domain.User) UserResponse {
+	return UserResponse{
+		UserID:   u.ID,
+		Username: u.Username,
+		TeamID:   u.TeamID,
+		TeamName: u.TeamName,
+		IsActive: u.IsActive,
+	}
+}
+
+func setETag(w http.ResponseWriter, pr *domain.PullRequest) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(pr.Version)+`"`)
+}
+
+func expectedVersion(r *http.Request, body *int) (int, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" || h == "*" {
+		if body != nil {
+			return *body, nil
+		}
+		return 0, nil
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(h, "W/"), `"`))
+	if err != nil || v <= 0 {
+		return 0, errors.New("invalid If-Match header")
+	}
+	if body != nil && *body != v {
+		return 0, errors.New("If-Match header and version field disagree")
+	}
+	return v, nil
+}
+
 func (s *Server) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
 	var req TeamAddRequest
 	if err := decodeStrict(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
-	_, err := s.teamSvc.CreateTeam(r.Context(), req.TeamName)
+	teamID, err := s.teamSvc.CreateTeam(r.Context(), req.TeamName)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "TEAM_CREATE_FAILED", err.Error())
 		return
 	}
 
 	for _, uid := range req.Users {
-		_, err := s.userSvc.GetUser(r.Context(), uid)
+		u, err := s.userSvc.GetUser(r.Context(), uid)
 		if err != nil {
 			writeError(w, http.StatusBadRequest, "USER_NOT_FOUND", "user not found: "+uid)
 			return
 		}
-		if err := s.userSvc.UpdateUser(r.Context(), domain.User{ID: uid, TeamName: &req.TeamName}); err != nil {
+		u.TeamID, u.TeamName = &teamID, nil
+		if _, err := s.userSvc.UpdateUser(r.Context(), *u); err != nil {
 			writeError(w, http.StatusInternalServerError, "USER_UPDATE_FAILED", err.Error())
 			return
 		}
 	}
-	writeJSON(w, http.StatusCreated, map[string]string{"team_name": req.TeamName})
+	writeJSON(w, http.StatusCreated, TeamAddResponse{TeamID: teamID, TeamName: req.TeamName})
 }
 
 func (s *Server) handlePRCreate(w http.ResponseWriter, r *http.Request) {
@@ -116,18 +324,24 @@ func (s *Server) handlePRCreate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
-	pr, err := s.prSvc.CreatePRWithAssignments(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	if !canActAs(principalFrom(r.Context()), req.AuthorID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only an admin can create a pr for another author")
+		return
+	}
+	in := pruc.CreateInput{PRID: req.PullRequestID, Name: req.PullRequestName, AuthorID: req.AuthorID}
+	if req.TargetTeam != nil {
+		in.TargetTeam = *req.TargetTeam
+	}
+	if req.Repository != nil {
+		in.Repository = *req.Repository
+	}
+	pr, err := s.prSvc.CreatePRWithAssignments(r.Context(), in)
 	if err != nil {
 		writeError(w, http.StatusConflict, "PR_CREATE_FAILED", err.Error())
 		return
 	}
-	resp := PullRequestResponse{
-		PullRequestID:   pr.ID,
-		PullRequestName: pr.Name,
-		AuthorID:        pr.AuthorID,
-		Status:          string(pr.Status),
-		Reviewers:       pr.AssignedReviewers,
-	}
+	resp := toPRResponse(pr)
+	setETag(w, pr)
 	writeJSON(w, http.StatusCreated, resp)
 }
 
@@ -137,27 +351,39 @@ func (s *Server) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
-	newID, pr, err := s.prSvc.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	version, err := expectedVersion(r, req.Version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	newID, pr, err := s.prSvc.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.NewUserID, version)
 	if err != nil {
 		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeConflict(w, err)
 		case errors.Is(err, domain.ErrPRMerged):
 			writeError(w, http.StatusConflict, "PR_MERGED", err.Error())
+		case errors.Is(err, domain.ErrPRClosed):
+			writeError(w, http.StatusConflict, "PR_CLOSED", err.Error())
 		case errors.Is(err, domain.ErrReviewerNotAssigned):
 			writeError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
 		case errors.Is(err, domain.ErrNoCandidate):
 			writeError(w, http.StatusConflict, "NO_CANDIDATE", err.Error())
+		case errors.Is(err, domain.ErrNotMember):
+			writeError(w, http.StatusConflict, "NOT_MEMBER", err.Error())
+		case errors.Is(err, domain.ErrReviewerInactive):
+			writeError(w, http.StatusConflict, "REVIEWER_INACTIVE", err.Error())
+		case errors.Is(err, domain.ErrAuthorReviewer):
+			writeError(w, http.StatusConflict, "AUTHOR_REVIEWER", err.Error())
+		case errors.Is(err, domain.ErrAlreadyAssigned):
+			writeError(w, http.StatusConflict, "ALREADY_ASSIGNED", err.Error())
 		default:
 			writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 		}
 		return
 	}
-	resp := PullRequestResponse{
-		PullRequestID:   pr.ID,
-		PullRequestName: pr.Name,
-		AuthorID:        pr.AuthorID,
-		Status:          string(pr.Status),
-		Reviewers:       pr.AssignedReviewers,
-	}
+	resp := toPRResponse(pr)
+	setETag(w, pr)
 	writeJSON(w, http.StatusOK, map[string]interface{}{"replaced_by": newID, "pr": resp})
 }
 
@@ -167,18 +393,34 @@ func (s *Server) handlePRMerge(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
-	pr, err := s.prSvc.MergePR(r.Context(), req.PullRequestID)
+	version, err := expectedVersion(r, req.Version)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", err.Error())
+		return
+	}
+	existing, err := s.prSvc.GetPR(r.Context(), req.PullRequestID)
 	if err != nil {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 		return
 	}
-	resp := PullRequestResponse{
-		PullRequestID:   pr.ID,
-		PullRequestName: pr.Name,
-		AuthorID:        pr.AuthorID,
-		Status:          string(pr.Status),
-		Reviewers:       pr.AssignedReviewers,
+	if !canManagePR(principalFrom(r.Context()), existing) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only the pr author or an admin can merge")
+		return
 	}
+	pr, err := s.prSvc.MergePR(r.Context(), req.PullRequestID, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			writeConflict(w, err)
+		case errors.Is(err, domain.ErrPRClosed):
+			writeError(w, http.StatusConflict, "PR_CLOSED", err.Error())
+		default:
+			writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		}
+		return
+	}
+	resp := toPRResponse(pr)
+	setETag(w, pr)
 	writeJSON(w, http.StatusOK, resp)
 }
 
@@ -191,13 +433,7 @@ func (s *Server) handleReviewerPRs(w http.ResponseWriter, r *http.Request) {
 	}
 	out := ReviewerPullRequestsResponse{}
 	for _, p := range prs {
-		out.PullRequests = append(out.PullRequests, PullRequestResponse{
-			PullRequestID:   p.ID,
-			PullRequestName: p.Name,
-			AuthorID:        p.AuthorID,
-			Status:          string(p.Status),
-			Reviewers:       p.AssignedReviewers,
-		})
+		out.PullRequests = append(out.PullRequests, toPRResponse(&p))
 	}
 	writeJSON(w, http.StatusOK, out)
 }
@@ -208,6 +444,10 @@ func (s *Server) handleUserCreate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
+	if req.TeamID != nil && req.TeamName != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "specify either team_id or team_name, not both")
+		return
+	}
 	isActive := true
 	if req.IsActive != nil {
 		isActive = *req.IsActive
@@ -215,7 +455,8 @@ func (s *Server) handleUserCreate(w http.ResponseWriter, r *http.Request) {
 	u := domain.User{
 		ID:       req.UserID,
 		Username: req.Username,
-		TeamName: req.TeamID,
+		TeamID:   req.TeamID,
+		TeamName: req.TeamName,
 		IsActive: isActive,
 	}
 	if err := s.userSvc.CreateUser(r.Context(), u); err != nil {
@@ -232,12 +473,7 @@ func (s *Server) handleUserGet(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 		return
 	}
-	var resp GetUserResponse
-	resp.User.UserID = u.ID
-	resp.User.Username = u.Username
-	resp.User.TeamID = u.TeamName
-	resp.User.IsActive = u.IsActive
-	writeJSON(w, http.StatusOK, resp)
+	writeJSON(w, http.StatusOK, GetUserResponse{User: toUserResponse(u)})
 }
 
 func (s *Server) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
@@ -246,6 +482,19 @@ func (s *Server) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
 		return
 	}
+	if req.TeamID != nil && req.TeamName != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "specify either team_id or team_name, not both")
+		return
+	}
+	t := principalFrom(r.Context())
+	if !canActAs(t, req.UserID) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", domain.ErrForbidden.Error())
+		return
+	}
+	if !t.IsAdmin() && (req.TeamID != nil || req.TeamName != nil || req.IsActive != nil) {
+		writeError(w, http.StatusForbidden, "FORBIDDEN", "only an admin can change team or activity")
+		return
+	}
 	existing, err := s.userSvc.GetUser(r.Context(), req.UserID)
 	if err != nil {
 		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
@@ -255,25 +504,70 @@ func (s *Server) handleUserUpdate(w http.ResponseWriter, r *http.Request) {
 		existing.Username = *req.Username
 	}
 	if req.TeamID != nil {
-		existing.TeamName = req.TeamID
+		existing.TeamID, existing.TeamName = req.TeamID, nil
+	}
+	if req.TeamName != nil {
+		existing.TeamID, existing.TeamName = nil, req.TeamName
 	}
 	if req.IsActive != nil {
 		existing.IsActive = *req.IsActive
 	}
-	if err := s.userSvc.UpdateUser(r.Context(), *existing); err != nil {
+	handoffs, err := s.userSvc.UpdateUser(r.Context(), *existing)
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeConflict(w, err)
+		return
+	case err != nil:
 		writeError(w, http.StatusBadRequest, "USER_UPDATE_FAILED", err.Error())
 		return
 	}
-	writeJSON(w, http.StatusOK, existing)
+	updated, err := s.userSvc.GetUser(r.Context(), existing.ID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, UserUpdateResponse{UserResponse: toUserResponse(updated), ReassignedReviews: toHandoffResponses(handoffs)})
 }
 
 func (s *Server) handleUserDelete(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "user_id")
-	if err := s.userSvc.DeleteUser(r.Context(), userID); err != nil {
+	handoffs, err := s.userSvc.DeleteUser(r.Context(), userID)
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeConflict(w, err)
+		return
+	case err != nil:
 		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+	writeJSON(w, http.StatusOK, UserDeleteResponse{Deleted: true, ReassignedReviews: toHandoffResponses(handoffs)})
+}
+
+func toHandoffResponses(in []domain.ReviewHandoff) []ReviewHandoffResponse {
+	out := make([]ReviewHandoffResponse, 0, len(in))
+	for _, h := range in {
+		out = append(out, ReviewHandoffResponse{
+			PullRequestID:   h.PRID,
+			PullRequestName: h.PRName,
+			OldReviewerID:   h.OldReviewerID,
+			NewReviewerID:   h.NewReviewerID,
+		})
+	}
+	return out
+}
+
+func (s *Server) handleUserRestore(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "user_id")
+	if err := s.userSvc.RestoreUser(r.Context(), userID); err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	u, err := s.userSvc.GetUser(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, GetUserResponse{User: toUserResponse(u)})
 }
 
 func (s *Server) handleTeamUpdate(w http.ResponseWriter, r *http.Request) {
@@ -294,9 +588,27 @@ func (s *Server) handleTeamUpdate(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleTeamDelete(w http.ResponseWriter, r *http.Request) {
 	teamName := chi.URLParam(r, "team_name")
-	if err := s.teamSvc.DeleteTeam(r.Context(), teamName); err != nil {
+	switch err := s.teamSvc.DeleteTeam(r.Context(), teamName); {
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeConflict(w, err)
+		return
+	case err != nil:
 		writeError(w, http.StatusBadRequest, "TEAM_DELETE_FAILED", err.Error())
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
 }
+
+func (s *Server) handleTeamRestore(w http.ResponseWriter, r *http.Request) {
+	team, err := s.teamSvc.RestoreTeam(r.Context(), chi.URLParam(r, "team_name"))
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, toTeamResponse(team))
+	case errors.Is(err, domain.ErrTeamNotFound):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrTeamNameTaken):
+		writeError(w, http.StatusConflict, "TEAM_EXISTS", err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, "TEAM_RESTORE_FAILED", err.Error())
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) handleTeamMembers(w http.ResponseWriter, r *http.Request) {
+	team, err := s.teamSvc.GetTeamByName(r.Context(), chi.URLParam(r, "team_name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toTeamResponse(team))
+}
+
+func (s *Server) handleTeamMemberAdd(w http.ResponseWriter, r *http.Request) {
+	var req TeamMemberRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	role := domain.MemberRoleMember
+	if req.Role != nil {
+		role = domain.MemberRole(*req.Role)
+	}
+	active := true
+	if req.IsActive != nil {
+		active = *req.IsActive
+	}
+	teamName := chi.URLParam(r, "team_name")
+	if err := s.teamSvc.AddMember(r.Context(), teamName, req.UserID, role, active); err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+	s.writeTeam(w, r, teamName, http.StatusCreated)
+}
+
+func (s *Server) handleTeamMemberUpdate(w http.ResponseWriter, r *http.Request) {
+	var req TeamMemberUpdateRequest
+	if err := decodeStrict(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid request")
+		return
+	}
+	var role *domain.MemberRole
+	if req.Role != nil {
+		rl := domain.MemberRole(*req.Role)
+		role = &rl
+	}
+	teamName := chi.URLParam(r, "team_name")
+	if err := s.teamSvc.UpdateMember(r.Context(), teamName, chi.URLParam(r, "user_id"), role, req.IsActive); err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+	s.writeTeam(w, r, teamName, http.StatusOK)
+}
+
+func (s *Server) handleTeamMemberRemove(w http.ResponseWriter, r *http.Request) {
+	if err := s.teamSvc.RemoveMember(r.Context(), chi.URLParam(r, "team_name"), chi.URLParam(r, "user_id")); err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func (s *Server) writeTeam(w http.ResponseWriter, r *http.Request, teamName string, code int) {
+	team, err := s.teamSvc.GetTeamByName(r.Context(), teamName)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	writeJSON(w, code, toTeamResponse(team))
+}
+
+func writeMembershipError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrTeamNotFound), errors.Is(err, domain.ErrNotMember):
+		writeError(w, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, domain.ErrVersionConflict):
+		writeConflict(w, err)
+	default:
+		writeError(w, http.StatusBadRequest, "MEMBERSHIP_UPDATE_FAILED", err.Error())
+	}
+}
+
+func toTeamResponse(t *domain.Team) TeamResponse {
+	resp := TeamResponse{TeamID: t.ID, TeamName: t.TeamName, Members: make([]TeamMemberResponse, 0, len(t.Members))}
+	for _, m := range t.Members {
+		resp.Members = append(resp.Members, TeamMemberResponse{
+			UserID:           m.UserID,
+			Username:         m.Username,
+			IsActive:         m.IsActive,
+			Role:             string(m.Role),
+			MembershipActive: m.MembershipActive,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	authuc "AvitoTestTask/internal/usecases/auth"
+	pruc "AvitoTestTask/internal/usecases/pullrequest"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	testAuthorID   = "11111111-1111-1111-1111-111111111111"
+	testReviewerID = "22222222-2222-2222-2222-222222222222"
+	testTeamID     = "33333333-3333-3333-3333-333333333333"
+	testPRID       = "55555555-5555-5555-5555-555555555555"
+)
+
+type fakeAuth struct {
+	authuc.Service
+}
+
+func (fakeAuth) Authenticate(_ context.Context, raw string) (*domain.APIToken, error) {
+	switch raw {
+	case "admin-token":
+		return &domain.APIToken{ID: "44444444-4444-4444-4444-444444444444", Role: domain.RoleAdmin}, nil
+	case "reviewer-token":
+		user := testReviewerID
+		return &domain.APIToken{ID: "66666666-6666-6666-6666-666666666666", Role: domain.RoleUser, UserID: &user}, nil
+	}
+	return nil, domain.ErrUnauthorized
+}
+
+// queryingPRRepo runs every write through the pgx query tracer the pool uses,
+// so the test sees the same SQL spans a real connection would produce.
+type queryingPRRepo struct {
+	pruc.Repository
+	tracer *tracing.QueryTracer
+}
+
+func (r queryingPRRepo) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
+	ctx = r.tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "INSERT INTO pull_requests"})
+	r.tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("INSERT 0 1")})
+	return nil
+}
+
+type stubUsers struct{}
+
+func (stubUsers) GetUserByID(_ context.Context, id string) (*domain.User, error) {
+	team := testTeamID
+	return &domain.User{ID: id, IsActive: true, TeamID: &team}, nil
+}
+
+type stubTeams struct{}
+
+func (stubTeams) GetTeamByID(_ context.Context, id string) (*domain.Team, error) {
+	return &domain.Team{ID: id, TeamName: "backend", Members: []domain.TeamMember{
+		{UserID: testAuthorID, IsActive: true, MembershipActive: true, Role: domain.MemberRoleMember},
+		{UserID: testReviewerID, IsActive: true, MembershipActive: true, Role: domain.MemberRoleMember},
+	}}, nil
+}
+
+func (t stubTeams) GetTeamByName(ctx context.Context, _ string) (*domain.Team, error) {
+	return t.GetTeamByID(ctx, testTeamID)
+}
+
+type stubCodeRepos struct {
+	pruc.CodeRepoRepository
+}
+
+func TestCreatePRSpanChain(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exp))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	repo := queryingPRRepo{tracer: tracing.NewQueryTracer()}
+	prSvc := pruc.NewTracedService(pruc.NewService(repo, stubTeams{}, stubUsers{}, stubCodeRepos{}))
+	srv := NewServer(fakeAuth{}, nil, nil, prSvc, nil, WithLogger(slog.New(slog.DiscardHandler)))
+
+	body := `{"pull_request_id":"` + testPRID + `","pull_request_name":"feature","author_id":"` + testAuthorID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	srv.r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	spans := exp.GetSpans()
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	httpSpan, ok := byName["POST /pullRequest/create"]
+	if !ok {
+		t.Fatalf("no http span, got %v", spanNames(spans))
+	}
+	ucSpan, ok := byName["pullrequest.CreatePRWithAssignments"]
+	if !ok {
+		t.Fatalf("no usecase span, got %v", spanNames(spans))
+	}
+	dbSpan, ok := byName["pgx.query"]
+	if !ok {
+		t.Fatalf("no query span, got %v", spanNames(spans))
+	}
+	if ucSpan.Parent.SpanID() != httpSpan.SpanContext.SpanID() {
+		t.Errorf("usecase span parent = %s, want http span %s", ucSpan.Parent.SpanID(), httpSpan.SpanContext.SpanID())
+	}
+	if dbSpan.Parent.SpanID() != ucSpan.SpanContext.SpanID() {
+		t.Errorf("query span parent = %s, want usecase span %s", dbSpan.Parent.SpanID(), ucSpan.SpanContext.SpanID())
+	}
+	if dbSpan.SpanContext.TraceID() != httpSpan.SpanContext.TraceID() {
+		t.Error("query span is not in the request trace")
+	}
+}
+
+func spanNames(spans tracetest.SpanStubs) []string {
+	out := make([]string, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, s.Name)
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookSenderPostsJSON(t *testing.T) {
+	var gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotType, gotBody = r.Header.Get("Content-Type"), string(b)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookSender().Send(context.Background(), srv.URL, `merged "pr-1"`); err != nil {
+		t.Fatal(err)
+	}
+	if gotType != "application/json" || gotBody != `{"text":"merged \"pr-1\""}` {
+		t.Errorf("content-type %q, body %s", gotType, gotBody)
+	}
+}
+
+func TestWebhookSenderReportsRejection(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookSender().Send(context.Background(), srv.URL, "hi")
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CodeRepoRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCodeRepoRepo(pool *pgxpool.Pool) *CodeRepoRepo {
+	return &CodeRepoRepo{pool: pool}
+}
+
+const codeRepoColumns = `
+SELECT r.id::text, r.name, r.vcs_url, t.id::text, t.team_name, r.reviewer_count, r.reviewer_strategy, r.created_at
+FROM repositories r
+LEFT JOIN teams t ON t.id = r.owning_team_id AND t.deleted_at IS NULL`
+
+func scanCodeRepo(row pgx.Row) (*domain.CodeRepository, error) {
+	var cr domain.CodeRepository
+	var strategy string
+	if err := row.Scan(&cr.ID, &cr.Name, &cr.VCSURL, &cr.OwningTeamID, &cr.OwningTeamName, &cr.Policy.ReviewerCount, &strategy, &cr.CreatedAt); err != nil {
+		return nil, err
+	}
+	cr.Policy.Strategy = domain.ReviewerStrategy(strategy)
+	return &cr, nil
+}
+
+func (r *CodeRepoRepo) CreateRepository(ctx context.Context, cr domain.CodeRepository) (string, error) {
+	var id string
+	if err := r.pool.QueryRow(ctx, "INSERT INTO repositories(name, vcs_url, owning_team_id, reviewer_count, reviewer_strategy) VALUES($1,$2,$3,$4,$5) RETURNING id::text",
+		cr.Name, cr.VCSURL, cr.OwningTeamID, cr.Policy.ReviewerCount, string(cr.Policy.Strategy)).Scan(&id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *CodeRepoRepo) GetRepositoryByName(ctx context.Context, name string) (*domain.CodeRepository, error) {
+	cr, err := scanCodeRepo(r.pool.QueryRow(ctx, codeRepoColumns+" WHERE r.name=$1", name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRepositoryNotFound
+	}
+	return cr, err
+}
+
+func (r *CodeRepoRepo) GetRepositoryByID(ctx context.Context, id string) (*domain.CodeRepository, error) {
+	cr, err := scanCodeRepo(r.pool.QueryRow(ctx, codeRepoColumns+" WHERE r.id=$1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrRepositoryNotFound
+	}
+	return cr, err
+}
+
+func (r *CodeRepoRepo) ListRepositories(ctx context.Context) ([]domain.CodeRepository, error) {
+	rows, err := r.pool.Query(ctx, codeRepoColumns+" ORDER BY r.name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.CodeRepository
+	for rows.Next() {
+		cr, err := scanCodeRepo(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *cr)
+	}
+	return out, rows.Err()
+}
+
+func (r *CodeRepoRepo) UpdateRepository(ctx context.Context, cr domain.CodeRepository) error {
+	ct, err := r.pool.Exec(ctx, "UPDATE repositories SET name=$2, vcs_url=$3, owning_team_id=$4, reviewer_count=$5, reviewer_strategy=$6 WHERE id=$1",
+		cr.ID, cr.Name, cr.VCSURL, cr.OwningTeamID, cr.Policy.ReviewerCount, string(cr.Policy.Strategy))
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrRepositoryNotFound
+	}
+	return nil
+}
+
+func (r *CodeRepoRepo) DeleteRepository(ctx context.Context, name string) error {
+	ct, err := r.pool.Exec(ctx, "DELETE FROM repositories WHERE name=$1", name)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrRepositoryNotFound
+	}
+	return nil
+}
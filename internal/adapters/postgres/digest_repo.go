@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DigestRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDigestRepo(pool *pgxpool.Pool) *DigestRepo {
+	return &DigestRepo{pool: pool}
+}
+
+func (r *DigestRepo) UpsertSubscription(ctx context.Context, sub domain.EmailSubscription) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO email_subscriptions(user_id, email, enabled) VALUES($1,$2,$3)
+ON CONFLICT (user_id) DO UPDATE SET email=EXCLUDED.email, enabled=EXCLUDED.enabled`, sub.UserID, sub.Email, sub.Enabled)
+	return err
+}
+
+func (r *DigestRepo) GetSubscription(ctx context.Context, userID string) (*domain.EmailSubscription, error) {
+	sub := domain.EmailSubscription{UserID: userID}
+	err := r.pool.QueryRow(ctx, "SELECT email, enabled, last_sent_at FROM email_subscriptions WHERE user_id=$1", userID).Scan(&sub.Email, &sub.Enabled, &sub.LastSentAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *DigestRepo) DeleteSubscription(ctx context.Context, userID string) error {
+	ct, err := r.pool.Exec(ctx, "DELETE FROM email_subscriptions WHERE user_id=$1", userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *DigestRepo) DueDigestRecipients(ctx context.Context, sentBefore time.Time) ([]domain.DigestRecipient, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT u.id::text, u.username, s.email
+FROM email_subscriptions s
+JOIN users u ON u.id = s.user_id
+WHERE s.enabled AND u.is_active AND u.deleted_at IS NULL AND (s.last_sent_at IS NULL OR s.last_sent_at < $1)
+ORDER BY u.id`, sentBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.DigestRecipient
+	for rows.Next() {
+		var rc domain.DigestRecipient
+		if err := rows.Scan(&rc.UserID, &rc.Username, &rc.Email); err != nil {
+			return nil, err
+		}
+		out = append(out, rc)
+	}
+	return out, rows.Err()
+}
+
+func (r *DigestRepo) MarkDigestSent(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.pool.Exec(ctx, "UPDATE email_subscriptions SET last_sent_at=$2 WHERE user_id=$1", userID, at)
+	return err
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdentityRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdentityRepo(pool *pgxpool.Pool) *IdentityRepo {
+	return &IdentityRepo{pool: pool}
+}
+
+func (r *IdentityRepo) LinkIdentity(ctx context.Context, id domain.ExternalIdentity) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO external_identities(provider, external_login, user_id) VALUES($1,$2,$3)
+ON CONFLICT (provider, external_login) DO UPDATE SET user_id=EXCLUDED.user_id`, id.Provider, id.Login, id.UserID)
+	return err
+}
+
+func (r *IdentityRepo) UnlinkIdentity(ctx context.Context, provider, login string) error {
+	ct, err := r.pool.Exec(ctx, "DELETE FROM external_identities WHERE provider=$1 AND external_login=$2", provider, login)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrUnknownIdentity
+	}
+	return nil
+}
+
+func (r *IdentityRepo) GetUserIDByLogin(ctx context.Context, provider, login string) (string, error) {
+	var uid string
+	if err := r.pool.QueryRow(ctx, `
+SELECT e.user_id::text FROM external_identities e
+JOIN users u ON u.id = e.user_id
+WHERE e.provider=$1 AND e.external_login=$2 AND u.deleted_at IS NULL`, provider, login).Scan(&uid); err != nil {
+		return "", err
+	}
+	return uid, nil
+}
+
+func (r *IdentityRepo) GetLoginsByUserIDs(ctx context.Context, provider string, userIDs []string) (map[string]string, error) {
+	rows, err := r.pool.Query(ctx, "SELECT user_id::text, external_login FROM external_identities WHERE provider=$1 AND user_id = ANY($2::uuid[])", provider, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]string, len(userIDs))
+	for rows.Next() {
+		var uid, login string
+		if err := rows.Scan(&uid, &login); err != nil {
+			return nil, err
+		}
+		out[uid] = login
+	}
+	return out, rows.Err()
+}
+
+func (r *IdentityRepo) ListIdentitiesForUser(ctx context.Context, userID string) ([]domain.ExternalIdentity, error) {
+	rows, err := r.pool.Query(ctx, "SELECT provider, external_login, user_id::text FROM external_identities WHERE user_id=$1 ORDER BY provider", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ExternalIdentity
+	for rows.Next() {
+		var id domain.ExternalIdentity
+		if err := rows.Scan(&id.Provider, &id.Login, &id.UserID); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
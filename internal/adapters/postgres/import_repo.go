@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportRepo(pool *pgxpool.Pool) *ImportRepo {
+	return &ImportRepo{pool: pool}
+}
+
+func (r *ImportRepo) ApplyImport(ctx context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{DryRun: dryRun, Rows: make([]domain.ImportRowResult, 0, len(rows))}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(ctx, tx)
+	teams := make(map[string]string)
+	for _, row := range rows {
+		res := domain.ImportRowResult{Row: row.Row, TeamName: row.TeamName, UserID: row.UserID, Status: domain.ImportUnchanged}
+		status, err := r.applyRow(ctx, tx, row, teams, report)
+		if err != nil {
+			res.Status, res.Error = domain.ImportError, err.Error()
+			report.Rows = append(report.Rows, res)
+			return report, err
+		}
+		res.Status = status
+		report.Rows = append(report.Rows, res)
+	}
+	if dryRun {
+		return report, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return report, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+func (r *ImportRepo) applyRow(ctx context.Context, tx pgx.Tx, row domain.ImportRow, teams map[string]string, report *domain.ImportReport) (string, error) {
+	status := domain.ImportUnchanged
+	teamID, ok := teams[row.TeamName]
+	if !ok {
+		err := tx.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1 AND deleted_at IS NULL", row.TeamName).Scan(&teamID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = tx.QueryRow(ctx, "INSERT INTO teams(team_name) VALUES($1) RETURNING id::text", row.TeamName).Scan(&teamID)
+			report.TeamsCreated++
+			status = domain.ImportCreated
+		}
+		if err != nil {
+			return "", err
+		}
+		teams[row.TeamName] = teamID
+	}
+	if row.UserID == "" {
+		return status, nil
+	}
+	var active, deleted bool
+	err := tx.QueryRow(ctx, "SELECT is_active, deleted_at IS NOT NULL FROM users WHERE id=$1 FOR UPDATE", row.UserID).Scan(&active, &deleted)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return "", err
+	case deleted:
+		return "", domain.ErrImportDeletedUser
+	case active && !row.IsActive:
+		return "", domain.ErrImportDeactivation
+	}
+	var inserted bool
+	err = tx.QueryRow(ctx, `
+INSERT INTO users(id, username, team_id, is_active) VALUES($1,$2,$3,$4)
+ON CONFLICT (id) DO UPDATE SET
+    username = EXCLUDED.username,
+    is_active = EXCLUDED.is_active,
+    team_id = COALESCE(users.team_id, EXCLUDED.team_id)
+WHERE (users.username, users.is_active, users.team_id IS NULL) IS DISTINCT FROM (EXCLUDED.username, EXCLUDED.is_active, false)
+RETURNING xmax = 0`, row.UserID, row.Username, teamID, row.IsActive).Scan(&inserted)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return "", err
+	case inserted:
+		status = domain.ImportCreated
+	case status == domain.ImportUnchanged:
+		status = domain.ImportUpdated
+	}
+	ct, err := tx.Exec(ctx, `
+INSERT INTO team_memberships(team_id, user_id, role) VALUES($1,$2,$3)
+ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role
+WHERE team_memberships.role IS DISTINCT FROM EXCLUDED.role`, teamID, row.UserID, string(row.Role))
+	if err != nil {
+		return "", err
+	}
+	if ct.RowsAffected() > 0 && status == domain.ImportUnchanged {
+		status = domain.ImportUpdated
+	}
+	return status, nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotifyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotifyRepo(pool *pgxpool.Pool) *NotifyRepo {
+	return &NotifyRepo{pool: pool}
+}
+
+func (r *NotifyRepo) GetTeamChatSettings(ctx context.Context, teamID string) (*domain.TeamChatSettings, error) {
+	s := domain.TeamChatSettings{TeamID: teamID}
+	var mode string
+	err := r.pool.QueryRow(ctx, "SELECT webhook_url, mode FROM team_chat_settings WHERE team_id=$1", teamID).Scan(&s.WebhookURL, &mode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrChatNotConfigured
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Mode = domain.ChatMode(mode)
+	return &s, nil
+}
+
+func (r *NotifyRepo) SetTeamChatSettings(ctx context.Context, s domain.TeamChatSettings) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO team_chat_settings(team_id, webhook_url, mode) VALUES($1,$2,$3)
+ON CONFLICT (team_id) DO UPDATE SET webhook_url=EXCLUDED.webhook_url, mode=EXCLUDED.mode, updated_at=now()`,
+		s.TeamID, s.WebhookURL, string(s.Mode))
+	return err
+}
+
+func (r *NotifyRepo) DeleteTeamChatSettings(ctx context.Context, teamID string) error {
+	ct, err := r.pool.Exec(ctx, "DELETE FROM team_chat_settings WHERE team_id=$1", teamID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrChatNotConfigured
+	}
+	return nil
+}
+
+func (r *NotifyRepo) GetChatRecipients(ctx context.Context, userIDs []string) (map[string]domain.ChatRecipient, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id::text, username, chat_opt_out FROM users WHERE id = ANY($1::uuid[])", userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]domain.ChatRecipient, len(userIDs))
+	for rows.Next() {
+		var rc domain.ChatRecipient
+		if err := rows.Scan(&rc.UserID, &rc.Username, &rc.OptedOut); err != nil {
+			return nil, err
+		}
+		out[rc.UserID] = rc
+	}
+	return out, rows.Err()
+}
+
+func (r *NotifyRepo) SetUserChatOptOut(ctx context.Context, userID string, optOut bool) error {
+	ct, err := r.pool.Exec(ctx, "UPDATE users SET chat_opt_out=$2 WHERE id=$1", userID, optOut)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *NotifyRepo) AddDigestItem(ctx context.Context, teamID, message string) error {
+	_, err := r.pool.Exec(ctx, "INSERT INTO chat_digest_items(team_id, message) VALUES($1,$2)", teamID, message)
+	return err
+}
+
+func (r *NotifyRepo) TakeDigestItems(ctx context.Context) (map[string][]string, error) {
+	rows, err := r.pool.Query(ctx, `
+DELETE FROM chat_digest_items
+WHERE id IN (SELECT id FROM chat_digest_items ORDER BY id FOR UPDATE SKIP LOCKED)
+RETURNING id, team_id::text, message`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	type item struct {
+		id      int64
+		teamID  string
+		message string
+	}
+	var items []item
+	for rows.Next() {
+		var it item
+		if err := rows.Scan(&it.id, &it.teamID, &it.message); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].id < items[j].id })
+	out := make(map[string][]string)
+	for _, it := range items {
+		out[it.teamID] = append(out[it.teamID], it.message)
+	}
+	return out, nil
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"AvitoTestTask/internal/domain"
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -19,56 +22,92 @@ func NewPRRepo(pool *pgxpool.Pool) *PRRepo {
 }
 
 func (r *PRRepo) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
-	_, err := r.pool.Exec(ctx, "INSERT INTO pull_requests(id, name, author_id, status, created_at) VALUES($1,$2,$3,$4,now())", pr.ID, pr.Name, pr.AuthorID, pr.Status)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	if len(pr.AssignedReviewers) > 0 {
-		parts := make([]string, 0, len(pr.AssignedReviewers))
-		args := make([]interface{}, 0, len(pr.AssignedReviewers)*2)
-		for i, uid := range pr.AssignedReviewers {
-			parts = append(parts, fmt.Sprintf("($%d,$%d)", i*2+1, i*2+2))
-			args = append(args, pr.ID, uid)
-		}
-		q := "INSERT INTO pull_request_reviewers(pull_request_id, user_id) VALUES " + strings.Join(parts, ",")
-		if _, err := r.pool.Exec(ctx, q, args...); err != nil {
-			return err
-		}
+	defer rollback(ctx, tx)
+	var extProvider, extRepo *string
+	var extNumber *int
+	if pr.External != nil {
+		extProvider, extRepo, extNumber = &pr.External.Provider, &pr.External.Repo, &pr.External.Number
 	}
-	return nil
+	if err := tx.QueryRow(ctx, `
+INSERT INTO pull_requests(id, name, author_id, team_id, repository_id, status, external_provider, external_repo, external_number, created_at)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,now())
+RETURNING version`, pr.ID, pr.Name, pr.AuthorID, nullIfEmpty(pr.TeamID), nullIfEmpty(pr.RepositoryID), pr.Status, extProvider, extRepo, extNumber).Scan(&pr.Version); err != nil {
+		return err
+	}
+	if err := insertReviewers(ctx, tx, pr.ID, pr.AssignedReviewers); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-func (r *PRRepo) SavePRReviewers(ctx context.Context, prID string, reviewerIDs []string) error {
+func (r *PRRepo) SavePRReviewers(ctx context.Context, prID string, version int, reviewerIDs []string) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(ctx)
+	defer rollback(ctx, tx)
+	if err := bumpVersion(ctx, tx, prID, version); err != nil {
+		return err
+	}
 	if _, err := tx.Exec(ctx, "DELETE FROM pull_request_reviewers WHERE pull_request_id=$1", prID); err != nil {
 		return err
 	}
-	if len(reviewerIDs) > 0 {
-		parts := make([]string, 0, len(reviewerIDs))
-		args := make([]interface{}, 0, len(reviewerIDs)*2)
-		for i, uid := range reviewerIDs {
-			parts = append(parts, fmt.Sprintf("($%d,$%d)", i*2+1, i*2+2))
-			args = append(args, prID, uid)
-		}
-		q := "INSERT INTO pull_request_reviewers(pull_request_id, user_id) VALUES " + strings.Join(parts, ",")
-		if _, err := tx.Exec(ctx, q, args...); err != nil {
-			return err
-		}
+	if err := insertReviewers(ctx, tx, prID, reviewerIDs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PRRepo) SavePRReviewerChange(ctx context.Context, prID string, version int, reviewerIDs []string, change domain.ReviewerChange) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	if err := bumpVersion(ctx, tx, prID, version); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM pull_request_reviewers WHERE pull_request_id=$1", prID); err != nil {
+		return err
+	}
+	if err := insertReviewers(ctx, tx, prID, reviewerIDs); err != nil {
+		return err
+	}
+	var actor *string
+	if change.ActorTokenID != "" {
+		actor = &change.ActorTokenID
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO pull_request_reviewer_changes(pull_request_id, user_id, action, forced, actor_token_id)
+VALUES($1,$2,$3,$4,$5)`, prID, change.UserID, string(change.Action), change.Forced, actor); err != nil {
+		return err
 	}
 	return tx.Commit(ctx)
 }
 
 func (r *PRRepo) GetPRByID(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	var id, name, authorID, status string
+	var id, name, authorID, teamID, repositoryID, status string
+	var version int
 	var createdAt, mergedAt *time.Time
-	if err := r.pool.QueryRow(ctx, "SELECT id, name, author_id::text, status, created_at, merged_at FROM pull_requests WHERE id=$1", prID).Scan(&id, &name, &authorID, &status, &createdAt, &mergedAt); err != nil {
+	var extProvider, extRepo *string
+	var extNumber *int
+	if err := r.pool.QueryRow(ctx, `
+SELECT id, name, COALESCE(author_id::text, ''), COALESCE(team_id::text, ''), COALESCE(repository_id::text, ''), status, version, created_at, merged_at,
+       external_provider, external_repo, external_number
+FROM pull_requests WHERE id=$1`, prID).Scan(&id, &name, &authorID, &teamID, &repositoryID, &status, &version, &createdAt, &mergedAt, &extProvider, &extRepo, &extNumber); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPRNotFound
+		}
 		return nil, err
 	}
-	pr := &domain.PullRequest{ID: id, Name: name, AuthorID: authorID, Status: domain.PRStatus(status), CreatedAt: createdAt, MergedAt: mergedAt}
+	pr := &domain.PullRequest{ID: id, Name: name, AuthorID: authorID, TeamID: teamID, RepositoryID: repositoryID, Status: domain.PRStatus(status), Version: version, CreatedAt: createdAt, MergedAt: mergedAt}
+	if extProvider != nil && extRepo != nil && extNumber != nil {
+		pr.External = &domain.ExternalRef{Provider: *extProvider, Repo: *extRepo, Number: *extNumber}
+	}
 	rows, err := r.pool.Query(ctx, "SELECT user_id::text FROM pull_request_reviewers WHERE pull_request_id=$1", prID)
 	if err != nil {
 		return nil, err
@@ -84,18 +123,26 @@ func (r *PRRepo) GetPRByID(ctx context.Context, prID string) (*domain.PullReques
 	return pr, nil
 }
 
-func (r *PRRepo) UpdatePRStatus(ctx context.Context, prID, status string) error {
+func (r *PRRepo) UpdatePRStatus(ctx context.Context, prID string, version int, status string) error {
+	var ct pgconn.CommandTag
+	var err error
 	if strings.ToUpper(status) == "MERGED" {
-		_, err := r.pool.Exec(ctx, "UPDATE pull_requests SET status='MERGED', merged_at=now() WHERE id=$1", prID)
+		ct, err = r.pool.Exec(ctx, "UPDATE pull_requests SET status='MERGED', merged_at=now(), version=version+1 WHERE id=$1 AND version=$2", prID, version)
+	} else {
+		ct, err = r.pool.Exec(ctx, "UPDATE pull_requests SET status=$3, version=version+1 WHERE id=$1 AND version=$2", prID, version, status)
+	}
+	if err != nil {
 		return err
 	}
-	_, err := r.pool.Exec(ctx, "UPDATE pull_requests SET status=$2 WHERE id=$1", prID, status)
-	return err
+	if ct.RowsAffected() == 0 {
+		return versionMismatch(ctx, r.pool, prID)
+	}
+	return nil
 }
 
 func (r *PRRepo) GetPRsForReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
 	rows, err := r.pool.Query(ctx, `
-SELECT pr.id, pr.name, pr.author_id::text, pr.status
+SELECT pr.id, pr.name, COALESCE(pr.author_id::text, ''), COALESCE(pr.team_id::text, ''), COALESCE(pr.repository_id::text, ''), pr.status, pr.version
 FROM pull_requests pr
 JOIN pull_request_reviewers rr ON pr.id = rr.pull_request_id
 WHERE rr.user_id = $1
@@ -108,7 +155,7 @@ ORDER BY pr.created_at DESC
 	var out []domain.PullRequest
 	for rows.Next() {
 		var p domain.PullRequest
-		if err := rows.Scan(&p.ID, &p.Name, &p.AuthorID, &p.Status); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.AuthorID, &p.TeamID, &p.RepositoryID, &p.Status, &p.Version); err != nil {
 			return nil, err
 		}
 		rvRows, err := r.pool.Query(ctx, "SELECT user_id::text FROM pull_request_reviewers WHERE pull_request_id=$1", p.ID)
@@ -125,12 +172,110 @@ ORDER BY pr.created_at DESC
 	return out, nil
 }
 
-func (r *PRRepo) UpdatePRName(ctx context.Context, prID, name string) error {
-	_, err := r.pool.Exec(ctx, "UPDATE pull_requests SET name=$1 WHERE id=$2", name, prID)
-	return err
+func (r *PRRepo) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT rr.user_id::text, count(*)
+FROM pull_request_reviewers rr
+JOIN pull_requests pr ON pr.id = rr.pull_request_id
+WHERE pr.status = 'OPEN' AND rr.user_id = ANY($1::uuid[])
+GROUP BY rr.user_id`, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var uid string
+		var n int
+		if err := rows.Scan(&uid, &n); err != nil {
+			return nil, err
+		}
+		out[uid] = n
+	}
+	return out, rows.Err()
+}
+
+func (r *PRRepo) UpdatePRName(ctx context.Context, prID string, version int, name string) error {
+	ct, err := r.pool.Exec(ctx, "UPDATE pull_requests SET name=$1, version=version+1 WHERE id=$2 AND version=$3", name, prID, version)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return versionMismatch(ctx, r.pool, prID)
+	}
+	return nil
 }
 
 func (r *PRRepo) DeletePR(ctx context.Context, prID string) error {
 	_, err := r.pool.Exec(ctx, "DELETE FROM pull_requests WHERE id=$1", prID)
 	return err
 }
+
+func insertReviewers(ctx context.Context, tx pgx.Tx, prID string, reviewerIDs []string) error {
+	if len(reviewerIDs) == 0 {
+		return nil
+	}
+	parts := make([]string, 0, len(reviewerIDs))
+	args := make([]interface{}, 0, len(reviewerIDs)*2)
+	for i, uid := range reviewerIDs {
+		parts = append(parts, fmt.Sprintf("($%d,$%d)", i*2+1, i*2+2))
+		args = append(args, prID, uid)
+	}
+	q := "INSERT INTO pull_request_reviewers(pull_request_id, user_id) VALUES " + strings.Join(parts, ",")
+	_, err := tx.Exec(ctx, q, args...)
+	return err
+}
+
+func bumpVersion(ctx context.Context, tx pgx.Tx, prID string, version int) error {
+	ct, err := tx.Exec(ctx, "UPDATE pull_requests SET version=version+1 WHERE id=$1 AND version=$2", prID, version)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return versionMismatch(ctx, tx, prID)
+	}
+	return nil
+}
+
+func applyHandoffs(ctx context.Context, tx pgx.Tx, handoffs []domain.ReviewHandoff) error {
+	bumped := make(map[string]bool)
+	for _, h := range handoffs {
+		if !bumped[h.PRID] {
+			if err := bumpVersion(ctx, tx, h.PRID, h.Version); err != nil {
+				return err
+			}
+			bumped[h.PRID] = true
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM pull_request_reviewers WHERE pull_request_id=$1 AND user_id=$2", h.PRID, h.OldReviewerID); err != nil {
+			return err
+		}
+		if h.NewReviewerID != "" {
+			if err := insertReviewers(ctx, tx, h.PRID, []string{h.NewReviewerID}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+func versionMismatch(ctx context.Context, q querier, prID string) error {
+	var exists bool
+	if err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id=$1)", prID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPRNotFound
+	}
+	return domain.ErrVersionConflict
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
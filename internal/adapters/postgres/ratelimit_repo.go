@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const rateLimitBucketTTL = 10 * time.Minute
+
+type RateLimitRepo struct {
+	pool      *pgxpool.Pool
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewRateLimitRepo(pool *pgxpool.Pool) *RateLimitRepo {
+	return &RateLimitRepo{pool: pool}
+}
+
+func (r *RateLimitRepo) Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, "INSERT INTO rate_limit_buckets(key, tokens, updated_at) VALUES($1,$2,clock_timestamp()) ON CONFLICT (key) DO NOTHING", key, float64(burst)); err != nil {
+		return false, 0, err
+	}
+	var tokens, elapsed float64
+	if err := tx.QueryRow(ctx, "SELECT tokens, EXTRACT(EPOCH FROM clock_timestamp() - updated_at)::float8 FROM rate_limit_buckets WHERE key=$1 FOR UPDATE", key).Scan(&tokens, &elapsed); err != nil {
+		return false, 0, err
+	}
+	tokens = math.Min(float64(burst), tokens+math.Max(elapsed, 0)*rate)
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	if _, err := tx.Exec(ctx, "UPDATE rate_limit_buckets SET tokens=$2, updated_at=clock_timestamp() WHERE key=$1", key, tokens); err != nil {
+		return false, 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, 0, err
+	}
+	r.prune(ctx)
+	if !allowed {
+		return false, time.Duration((1 - tokens) / rate * float64(time.Second)), nil
+	}
+	return true, 0, nil
+}
+
+func (r *RateLimitRepo) prune(ctx context.Context) {
+	r.mu.Lock()
+	if time.Since(r.lastPrune) < time.Minute {
+		r.mu.Unlock()
+		return
+	}
+	r.lastPrune = time.Now()
+	r.mu.Unlock()
+	if _, err := r.pool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < clock_timestamp() - make_interval(secs => $1)", rateLimitBucketTTL.Seconds()); err != nil {
+		logging.FromContext(ctx).Warn("prune rate limit buckets", "err", err)
+	}
+}
@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SnapshotRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSnapshotRepo(pool *pgxpool.Pool) *SnapshotRepo {
+	return &SnapshotRepo{pool: pool}
+}
+
+func (r *SnapshotRepo) ExportSnapshot(ctx context.Context) (*domain.Snapshot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(ctx, tx)
+	snap := &domain.Snapshot{
+		FormatVersion: domain.SnapshotFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Teams:         []domain.SnapshotTeam{},
+		Users:         []domain.SnapshotUser{},
+		Memberships:   []domain.SnapshotMembership{},
+		Repositories:  []domain.SnapshotRepository{},
+		PullRequests:  []domain.SnapshotPR{},
+
+		ExternalIdentities: []domain.SnapshotIdentity{},
+		ChatSettings:       []domain.SnapshotChatSettings{},
+		EmailSubscriptions: []domain.SnapshotEmailSubscription{},
+	}
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(max(version), 0) FROM schema_migrations").Scan(&snap.SchemaVersion); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT id::text, team_name, created_at, deleted_at FROM teams ORDER BY created_at, id", func(rows pgx.Rows) error {
+		var t domain.SnapshotTeam
+		if err := rows.Scan(&t.ID, &t.TeamName, &t.CreatedAt, &t.DeletedAt); err != nil {
+			return err
+		}
+		snap.Teams = append(snap.Teams, t)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT id::text, username, team_id::text, is_active, chat_opt_out, created_at, deleted_at FROM users ORDER BY created_at, id", func(rows pgx.Rows) error {
+		var u domain.SnapshotUser
+		var active *bool
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamID, &active, &u.ChatOptOut, &u.CreatedAt, &u.DeletedAt); err != nil {
+			return err
+		}
+		u.IsActive = active == nil || *active
+		snap.Users = append(snap.Users, u)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT team_id::text, user_id::text, role, is_active, created_at FROM team_memberships ORDER BY team_id, user_id", func(rows pgx.Rows) error {
+		var m domain.SnapshotMembership
+		var role string
+		if err := rows.Scan(&m.TeamID, &m.UserID, &role, &m.IsActive, &m.CreatedAt); err != nil {
+			return err
+		}
+		m.Role = domain.MemberRole(role)
+		snap.Memberships = append(snap.Memberships, m)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT id::text, name, vcs_url, owning_team_id::text, reviewer_count, reviewer_strategy, created_at FROM repositories ORDER BY name", func(rows pgx.Rows) error {
+		var cr domain.SnapshotRepository
+		var strategy string
+		if err := rows.Scan(&cr.ID, &cr.Name, &cr.VCSURL, &cr.OwningTeamID, &cr.ReviewerCount, &strategy, &cr.CreatedAt); err != nil {
+			return err
+		}
+		cr.ReviewerStrategy = domain.ReviewerStrategy(strategy)
+		snap.Repositories = append(snap.Repositories, cr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	index := make(map[string]int)
+	if err := collect(ctx, tx, `
+SELECT id, COALESCE(name, ''), author_id::text, team_id::text, repository_id::text, status, version,
+       external_provider, external_repo, external_number, created_at, merged_at
+FROM pull_requests ORDER BY created_at, id`, func(rows pgx.Rows) error {
+		var pr domain.SnapshotPR
+		var status string
+		var provider, repo *string
+		var number *int
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.TeamID, &pr.RepositoryID, &status, &pr.Version,
+			&provider, &repo, &number, &pr.CreatedAt, &pr.MergedAt); err != nil {
+			return err
+		}
+		pr.Status = domain.PRStatus(status)
+		if provider != nil && repo != nil && number != nil {
+			pr.External = &domain.ExternalRef{Provider: *provider, Repo: *repo, Number: *number}
+		}
+		pr.Reviewers = []domain.SnapshotReviewer{}
+		pr.ReviewerChanges = []domain.SnapshotReviewerChange{}
+		index[pr.ID] = len(snap.PullRequests)
+		snap.PullRequests = append(snap.PullRequests, pr)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT pull_request_id, user_id::text, assigned_at FROM pull_request_reviewers ORDER BY pull_request_id, assigned_at, user_id", func(rows pgx.Rows) error {
+		var prID string
+		var rv domain.SnapshotReviewer
+		if err := rows.Scan(&prID, &rv.UserID, &rv.AssignedAt); err != nil {
+			return err
+		}
+		if i, ok := index[prID]; ok {
+			snap.PullRequests[i].Reviewers = append(snap.PullRequests[i].Reviewers, rv)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT pull_request_id, user_id::text, action, forced, created_at FROM pull_request_reviewer_changes ORDER BY id", func(rows pgx.Rows) error {
+		var prID, action string
+		var c domain.SnapshotReviewerChange
+		if err := rows.Scan(&prID, &c.UserID, &action, &c.Forced, &c.CreatedAt); err != nil {
+			return err
+		}
+		c.Action = domain.ReviewerChangeAction(action)
+		if i, ok := index[prID]; ok {
+			snap.PullRequests[i].ReviewerChanges = append(snap.PullRequests[i].ReviewerChanges, c)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT provider, external_login, user_id::text, created_at FROM external_identities ORDER BY provider, external_login", func(rows pgx.Rows) error {
+		var id domain.SnapshotIdentity
+		if err := rows.Scan(&id.Provider, &id.Login, &id.UserID, &id.CreatedAt); err != nil {
+			return err
+		}
+		snap.ExternalIdentities = append(snap.ExternalIdentities, id)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT team_id::text, webhook_url, mode FROM team_chat_settings ORDER BY team_id", func(rows pgx.Rows) error {
+		var cs domain.SnapshotChatSettings
+		var mode string
+		if err := rows.Scan(&cs.TeamID, &cs.WebhookURL, &mode); err != nil {
+			return err
+		}
+		cs.Mode = domain.ChatMode(mode)
+		snap.ChatSettings = append(snap.ChatSettings, cs)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(ctx, tx, "SELECT user_id::text, email, enabled, last_sent_at, created_at FROM email_subscriptions ORDER BY user_id", func(rows pgx.Rows) error {
+		var es domain.SnapshotEmailSubscription
+		if err := rows.Scan(&es.UserID, &es.Email, &es.Enabled, &es.LastSentAt, &es.CreatedAt); err != nil {
+			return err
+		}
+		snap.EmailSubscriptions = append(snap.EmailSubscriptions, es)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+func (r *SnapshotRepo) SchemaVersion(ctx context.Context) (int, error) {
+	var v int
+	err := r.pool.QueryRow(ctx, "SELECT COALESCE(max(version), 0) FROM schema_migrations").Scan(&v)
+	return v, err
+}
+
+func (r *SnapshotRepo) RestoreSnapshot(ctx context.Context, snap *domain.Snapshot) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, "LOCK TABLE teams, users, pull_requests, repositories IN EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+	var populated bool
+	if err := tx.QueryRow(ctx, `
+SELECT EXISTS(SELECT 1 FROM teams) OR EXISTS(SELECT 1 FROM users)
+    OR EXISTS(SELECT 1 FROM pull_requests) OR EXISTS(SELECT 1 FROM repositories)`).Scan(&populated); err != nil {
+		return err
+	}
+	if populated {
+		return domain.ErrRestoreNotEmpty
+	}
+	batch := &pgx.Batch{}
+	for _, t := range snap.Teams {
+		batch.Queue("INSERT INTO teams(id, team_name, created_at, deleted_at) VALUES($1,$2,COALESCE($3, now()),$4)", t.ID, t.TeamName, t.CreatedAt, t.DeletedAt)
+	}
+	for _, u := range snap.Users {
+		batch.Queue("INSERT INTO users(id, username, team_id, is_active, chat_opt_out, created_at, deleted_at) VALUES($1,$2,$3,$4,$5,COALESCE($6, now()),$7)", u.ID, u.Username, u.TeamID, u.IsActive, u.ChatOptOut, u.CreatedAt, u.DeletedAt)
+	}
+	for _, m := range snap.Memberships {
+		batch.Queue("INSERT INTO team_memberships(team_id, user_id, role, is_active, created_at) VALUES($1,$2,$3,$4,COALESCE($5, now()))", m.TeamID, m.UserID, string(m.Role), m.IsActive, m.CreatedAt)
+	}
+	for _, cr := range snap.Repositories {
+		batch.Queue(`
+INSERT INTO repositories(id, name, vcs_url, owning_team_id, reviewer_count, reviewer_strategy, created_at)
+VALUES($1,$2,$3,$4,$5,$6,COALESCE($7, now()))`, cr.ID, cr.Name, cr.VCSURL, cr.OwningTeamID, cr.ReviewerCount, string(cr.ReviewerStrategy), cr.CreatedAt)
+	}
+	for _, pr := range snap.PullRequests {
+		var provider, repo *string
+		var number *int
+		if pr.External != nil {
+			provider, repo, number = &pr.External.Provider, &pr.External.Repo, &pr.External.Number
+		}
+		batch.Queue(`
+INSERT INTO pull_requests(id, name, author_id, team_id, repository_id, status, version,
+    external_provider, external_repo, external_number, created_at, merged_at)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,COALESCE($11, now()),$12)`,
+			pr.ID, pr.Name, pr.AuthorID, pr.TeamID, pr.RepositoryID, string(pr.Status), pr.Version,
+			provider, repo, number, pr.CreatedAt, pr.MergedAt)
+		for _, rv := range pr.Reviewers {
+			batch.Queue("INSERT INTO pull_request_reviewers(pull_request_id, user_id, assigned_at) VALUES($1,$2,COALESCE($3, now()))", pr.ID, rv.UserID, rv.AssignedAt)
+		}
+		for _, c := range pr.ReviewerChanges {
+			batch.Queue("INSERT INTO pull_request_reviewer_changes(pull_request_id, user_id, action, forced, created_at) VALUES($1,$2,$3,$4,COALESCE($5, now()))", pr.ID, c.UserID, string(c.Action), c.Forced, c.CreatedAt)
+		}
+	}
+	for _, id := range snap.ExternalIdentities {
+		batch.Queue("INSERT INTO external_identities(provider, external_login, user_id, created_at) VALUES($1,$2,$3,COALESCE($4, now()))", id.Provider, id.Login, id.UserID, id.CreatedAt)
+	}
+	for _, cs := range snap.ChatSettings {
+		batch.Queue("INSERT INTO team_chat_settings(team_id, webhook_url, mode) VALUES($1,$2,$3)", cs.TeamID, cs.WebhookURL, string(cs.Mode))
+	}
+	for _, es := range snap.EmailSubscriptions {
+		batch.Queue("INSERT INTO email_subscriptions(user_id, email, enabled, last_sent_at, created_at) VALUES($1,$2,$3,$4,COALESCE($5, now()))", es.UserID, es.Email, es.Enabled, es.LastSentAt, es.CreatedAt)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func collect(ctx context.Context, tx pgx.Tx, query string, scan func(pgx.Rows) error) error {
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
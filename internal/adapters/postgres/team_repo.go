@@ -5,8 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,16 +26,12 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) (string, err
 	if err != nil {
 		return "", err
 	}
-	defer func() {
-		if err := tx.Rollback(ctx); err != nil {
-			log.Printf("warning: failed to rollback transaction: %v", err)
-		}
-	}()
-	if _, err := tx.Exec(ctx, "INSERT INTO teams(team_name) VALUES($1) ON CONFLICT (team_name) DO NOTHING", teamName); err != nil {
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, "INSERT INTO teams(team_name) VALUES($1) ON CONFLICT (team_name) WHERE deleted_at IS NULL DO NOTHING", teamName); err != nil {
 		return "", err
 	}
 	var id string
-	if err := tx.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1", teamName).Scan(&id); err != nil {
+	if err := tx.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1 AND deleted_at IS NULL", teamName).Scan(&id); err != nil {
 		return "", err
 	}
 	if err := tx.Commit(ctx); err != nil {
@@ -41,57 +40,252 @@ func (r *TeamRepo) CreateTeam(ctx context.Context, teamName string) (string, err
 	return id, nil
 }
 
+func (r *TeamRepo) CreateTeamWithMembers(ctx context.Context, teamName string, members []string) (string, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer rollback(ctx, tx)
+	var id string
+	err = tx.QueryRow(ctx, "INSERT INTO teams(team_name) VALUES($1) RETURNING id::text", teamName).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return "", domain.ErrTeamNameTaken
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := insertMembers(ctx, tx, id, members); err != nil {
+		return "", err
+	}
+	return id, tx.Commit(ctx)
+}
+
+func (r *TeamRepo) ReplaceTeam(ctx context.Context, teamID, teamName string, members []string, handoffs []domain.ReviewHandoff) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	var current string
+	if err := tx.QueryRow(ctx, "SELECT team_name FROM teams WHERE id=$1 AND deleted_at IS NULL FOR UPDATE", teamID).Scan(&current); err != nil {
+		return domain.ErrTeamNotFound
+	}
+	if teamName != current {
+		_, err := tx.Exec(ctx, "UPDATE teams SET team_name=$1 WHERE id=$2", teamName, teamID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.ErrTeamNameTaken
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+WITH removed AS (
+    DELETE FROM team_memberships m
+    USING users u
+    WHERE m.team_id=$1 AND u.id = m.user_id AND u.deleted_at IS NULL AND NOT (m.user_id = ANY($2::uuid[]))
+    RETURNING m.user_id
+)
+UPDATE users SET team_id=NULL WHERE team_id=$1 AND id IN (SELECT user_id FROM removed)`, teamID, members); err != nil {
+		return err
+	}
+	if err := insertMembers(ctx, tx, teamID, members); err != nil {
+		return err
+	}
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func insertMembers(ctx context.Context, tx pgx.Tx, teamID string, members []string) error {
+	for _, uid := range members {
+		_, err := tx.Exec(ctx, "INSERT INTO team_memberships(team_id, user_id) VALUES($1,$2) ON CONFLICT DO NOTHING", teamID, uid)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("member %s: user not found", uid)
+		}
+		if err != nil {
+			return fmt.Errorf("member %s: %w", uid, err)
+		}
+	}
+	return nil
+}
+
 func (r *TeamRepo) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
 	var teamID string
-	if err := r.pool.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1", teamName).Scan(&teamID); err != nil {
-		return nil, errors.New("team not found")
+	if err := r.pool.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1 AND deleted_at IS NULL", teamName).Scan(&teamID); err != nil {
+		return nil, domain.ErrTeamNotFound
+	}
+	return r.loadMembers(ctx, teamID, teamName)
+}
+
+func (r *TeamRepo) GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error) {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return nil, domain.ErrInvalidID
+	}
+	var teamName string
+	if err := r.pool.QueryRow(ctx, "SELECT team_name FROM teams WHERE id=$1 AND deleted_at IS NULL", teamID).Scan(&teamName); err != nil {
+		return nil, domain.ErrTeamNotFound
+	}
+	return r.loadMembers(ctx, teamID, teamName)
+}
+
+func (r *TeamRepo) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id::text, team_name FROM teams WHERE deleted_at IS NULL ORDER BY team_name")
+	if err != nil {
+		return nil, err
+	}
+	type ref struct{ id, name string }
+	var refs []ref
+	for rows.Next() {
+		var t ref
+		if err := rows.Scan(&t.id, &t.name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		refs = append(refs, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]domain.Team, 0, len(refs))
+	for _, t := range refs {
+		team, err := r.loadMembers(ctx, t.id, t.name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *team)
 	}
-	rows, err := r.pool.Query(ctx, "SELECT id::text, username, is_active FROM users WHERE team_id=$1", teamID)
+	return out, nil
+}
+
+func (r *TeamRepo) loadMembers(ctx context.Context, teamID, teamName string) (*domain.Team, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT u.id::text, u.username, u.is_active, m.role, m.is_active
+FROM team_memberships m
+JOIN users u ON u.id = m.user_id
+WHERE m.team_id=$1 AND u.deleted_at IS NULL
+ORDER BY m.created_at, u.id`, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var members []domain.TeamMember
 	for rows.Next() {
-		var uid, uname string
-		var active bool
-		if err := rows.Scan(&uid, &uname, &active); err != nil {
+		var m domain.TeamMember
+		var role string
+		if err := rows.Scan(&m.UserID, &m.Username, &m.IsActive, &role, &m.MembershipActive); err != nil {
 			return nil, err
 		}
-		members = append(members, domain.TeamMember{UserID: uid, Username: uname, IsActive: active})
+		m.Role = domain.MemberRole(role)
+		members = append(members, m)
 	}
-	return &domain.Team{TeamName: teamName, Members: members}, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &domain.Team{ID: teamID, TeamName: teamName, Members: members}, nil
 }
 
 func (r *TeamRepo) UpdateTeam(ctx context.Context, oldName, newName string) error {
-	ct, err := r.pool.Exec(ctx, "UPDATE teams SET team_name=$1 WHERE team_name=$2", newName, oldName)
+	ct, err := r.pool.Exec(ctx, "UPDATE teams SET team_name=$1 WHERE team_name=$2 AND deleted_at IS NULL", newName, oldName)
 	if err != nil {
 		return err
 	}
 	if ct.RowsAffected() == 0 {
-		return fmt.Errorf("team not found")
+		return domain.ErrTeamNotFound
 	}
 	return nil
 }
 
-func (r *TeamRepo) DeleteTeam(ctx context.Context, teamName string) error {
+func (r *TeamRepo) DeleteTeam(ctx context.Context, teamName string, handoffs []domain.ReviewHandoff) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := tx.Rollback(ctx); err != nil {
-			log.Printf("warning: failed to rollback transaction: %v", err)
-		}
-	}()
-	var teamID string
-	if err := tx.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1", teamName).Scan(&teamID); err != nil {
-		return fmt.Errorf("team not found")
+	defer rollback(ctx, tx)
+	ct, err := tx.Exec(ctx, "UPDATE teams SET deleted_at=now() WHERE team_name=$1 AND deleted_at IS NULL", teamName)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrTeamNotFound
+	}
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *TeamRepo) RestoreTeam(ctx context.Context, teamName string) (string, error) {
+	var id string
+	err := r.pool.QueryRow(ctx, `
+UPDATE teams SET deleted_at=NULL
+WHERE id = (SELECT id FROM teams WHERE team_name=$1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT 1)
+RETURNING id::text`, teamName).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", domain.ErrTeamNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return "", domain.ErrTeamNameTaken
+	}
+	return id, err
+}
+
+func (r *TeamRepo) PurgeDeletedTeams(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, "UPDATE users SET team_id=NULL WHERE team_id IN (SELECT id FROM teams WHERE deleted_at < $1)", before); err != nil {
+		return 0, err
+	}
+	ct, err := tx.Exec(ctx, "DELETE FROM teams WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), tx.Commit(ctx)
+}
+
+func (r *TeamRepo) UpsertMembership(ctx context.Context, teamID, userID string, role domain.MemberRole, active bool, handoffs []domain.ReviewHandoff) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, `
+INSERT INTO team_memberships(team_id, user_id, role, is_active) VALUES($1,$2,$3,$4)
+ON CONFLICT (team_id, user_id) DO UPDATE SET role=EXCLUDED.role, is_active=EXCLUDED.is_active`, teamID, userID, string(role), active); err != nil {
+		return err
+	}
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *TeamRepo) DeleteMembership(ctx context.Context, teamID, userID string, handoffs []domain.ReviewHandoff) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	ct, err := tx.Exec(ctx, "DELETE FROM team_memberships WHERE team_id=$1 AND user_id=$2", teamID, userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrNotMember
 	}
-	if _, err := tx.Exec(ctx, "UPDATE users SET team_id=NULL WHERE team_id=$1::uuid", teamID); err != nil {
+	if _, err := tx.Exec(ctx, "UPDATE users SET team_id=NULL WHERE id=$1 AND team_id=$2", userID, teamID); err != nil {
 		return err
 	}
-	if _, err := tx.Exec(ctx, "DELETE FROM teams WHERE id=$1::uuid", teamID); err != nil {
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
 		return err
 	}
 	return tx.Commit(ctx)
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TokenRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTokenRepo(pool *pgxpool.Pool) *TokenRepo {
+	return &TokenRepo{pool: pool}
+}
+
+func (r *TokenRepo) CreateToken(ctx context.Context, t domain.APIToken, tokenHash string) (*domain.APIToken, error) {
+	if err := r.pool.QueryRow(ctx, "INSERT INTO api_tokens(name, token_hash, role, user_id) VALUES($1,$2,$3,$4) RETURNING id::text, created_at", t.Name, tokenHash, string(t.Role), t.UserID).Scan(&t.ID, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TokenRepo) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	var t domain.APIToken
+	var role string
+	if err := r.pool.QueryRow(ctx, `
+SELECT t.id::text, t.name, t.role, t.user_id::text, t.created_at
+FROM api_tokens t
+LEFT JOIN users u ON u.id = t.user_id
+WHERE t.token_hash=$1 AND t.revoked_at IS NULL AND (t.user_id IS NULL OR u.deleted_at IS NULL)`, tokenHash).Scan(&t.ID, &t.Name, &role, &t.UserID, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	t.Role = domain.Role(role)
+	return &t, nil
+}
+
+func (r *TokenRepo) ListTokens(ctx context.Context) ([]domain.APIToken, error) {
+	rows, err := r.pool.Query(ctx, "SELECT id::text, name, role, user_id::text, created_at, revoked_at FROM api_tokens ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.APIToken
+	for rows.Next() {
+		var t domain.APIToken
+		var role string
+		if err := rows.Scan(&t.ID, &t.Name, &role, &t.UserID, &t.CreatedAt, &t.RevokedAt); err != nil {
+			return nil, err
+		}
+		t.Role = domain.Role(role)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *TokenRepo) RevokeToken(ctx context.Context, tokenID string) error {
+	ct, err := r.pool.Exec(ctx, "UPDATE api_tokens SET revoked_at=now() WHERE id=$1 AND revoked_at IS NULL", tokenID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.New("token not found")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func rollback(ctx context.Context, tx pgx.Tx) {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		logging.FromContext(ctx).Warn("failed to rollback transaction", "err", err)
+	}
+}
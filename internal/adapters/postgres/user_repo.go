@@ -5,9 +5,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -23,105 +24,200 @@ func (r *UserRepo) CreateUser(ctx context.Context, u domain.User) error {
 	if _, err := uuid.Parse(u.ID); err != nil {
 		return err
 	}
-	var teamUUID *uuid.UUID
-	if u.TeamName != nil {
-		t, err := uuid.Parse(*u.TeamName)
-		if err != nil {
-			return err
+	if u.TeamID != nil {
+		if _, err := uuid.Parse(*u.TeamID); err != nil {
+			return domain.ErrInvalidID
 		}
-		teamUUID = &t
 	}
-	if teamUUID != nil {
-		_, err := r.pool.Exec(ctx, "INSERT INTO users(id, username, team_id, is_active, created_at) VALUES($1,$2,$3,$4,now())", u.ID, u.Username, *teamUUID, u.IsActive)
-		if err != nil {
-			return fmt.Errorf("create user: %w", err)
-		}
-	} else {
-		_, err := r.pool.Exec(ctx, "INSERT INTO users(id, username, is_active, created_at) VALUES($1,$2,$3,now())", u.ID, u.Username, u.IsActive)
-		if err != nil {
-			return fmt.Errorf("create user: %w", err)
-		}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
 	}
-	return nil
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, "INSERT INTO users(id, username, team_id, is_active, created_at) VALUES($1,$2,$3,$4,now())", u.ID, u.Username, u.TeamID, u.IsActive); err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+	if err := syncPrimaryMembership(ctx, tx, u.ID, nil, u.TeamID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *UserRepo) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	if _, err := uuid.Parse(userID); err != nil {
 		return nil, err
 	}
-	var username string
-	var teamID *string
-	var isActive bool
-	if err := r.pool.QueryRow(ctx, "SELECT username, team_id::text, is_active FROM users WHERE id=$1", userID).Scan(&username, &teamID, &isActive); err != nil {
+	u := domain.User{ID: userID}
+	if err := r.pool.QueryRow(ctx, `
+SELECT u.username, t.id::text, t.team_name, u.is_active
+FROM users u
+LEFT JOIN teams t ON t.id = u.team_id AND t.deleted_at IS NULL
+WHERE u.id=$1 AND u.deleted_at IS NULL`, userID).Scan(&u.Username, &u.TeamID, &u.TeamName, &u.IsActive); err != nil {
 		return nil, err
 	}
-	return &domain.User{ID: userID, Username: username, TeamName: teamID, IsActive: isActive}, nil
+	return &u, nil
 }
 
-func (r *UserRepo) UpdateUser(ctx context.Context, u domain.User) error {
+func (r *UserRepo) ListUsers(ctx context.Context, username string) ([]domain.User, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT u.id::text, u.username, t.id::text, t.team_name, u.is_active
+FROM users u
+LEFT JOIN teams t ON t.id = u.team_id AND t.deleted_at IS NULL
+WHERE u.deleted_at IS NULL AND ($1 = '' OR u.username = $1)
+ORDER BY u.created_at, u.id`, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamID, &u.TeamName, &u.IsActive); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (r *UserRepo) UpdateUser(ctx context.Context, u domain.User, handoffs []domain.ReviewHandoff) error {
 	if _, err := uuid.Parse(u.ID); err != nil {
 		return err
 	}
+	if u.TeamID != nil {
+		if _, err := uuid.Parse(*u.TeamID); err != nil {
+			return domain.ErrInvalidID
+		}
+	}
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if err := tx.Rollback(ctx); err != nil {
-			log.Printf("warning: failed to rollback transaction: %v", err)
-		}
-	}()
-	if _, err := tx.Exec(ctx, "UPDATE users SET username=$1, is_active=$2 WHERE id=$3", u.Username, u.IsActive, u.ID); err != nil {
+	defer rollback(ctx, tx)
+	var oldTeamID *string
+	if err := tx.QueryRow(ctx, "SELECT team_id::text FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE", u.ID).Scan(&oldTeamID); err != nil {
+		return errors.New("user not found")
+	}
+	if _, err := tx.Exec(ctx, "UPDATE users SET username=$1, is_active=$2, team_id=$3 WHERE id=$4", u.Username, u.IsActive, u.TeamID, u.ID); err != nil {
 		return err
 	}
-	if u.TeamName == nil {
-		if _, err := tx.Exec(ctx, "UPDATE users SET team_id=NULL WHERE id=$1", u.ID); err != nil {
-			return err
-		}
-	} else {
-		if _, err := uuid.Parse(*u.TeamName); err == nil {
-			if _, err := tx.Exec(ctx, "UPDATE users SET team_id=$1 WHERE id=$2", *u.TeamName, u.ID); err != nil {
-				return err
-			}
-		} else {
-			var teamID string
-			if err := tx.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1", *u.TeamName).Scan(&teamID); err != nil {
-				return errors.New("team not found")
-			}
-			if _, err := tx.Exec(ctx, "UPDATE users SET team_id=$1::uuid WHERE id=$2", teamID, u.ID); err != nil {
-				return err
-			}
-		}
+	if err := syncPrimaryMembership(ctx, tx, u.ID, oldTeamID, u.TeamID); err != nil {
+		return err
+	}
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
+		return err
 	}
 	return tx.Commit(ctx)
 }
 
-func (r *UserRepo) DeleteUser(ctx context.Context, userID string) error {
+func (r *UserRepo) DeleteUser(ctx context.Context, userID string, handoffs []domain.ReviewHandoff) error {
 	if _, err := uuid.Parse(userID); err != nil {
 		return err
 	}
-	ct, err := r.pool.Exec(ctx, "DELETE FROM users WHERE id=$1", userID)
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	ct, err := tx.Exec(ctx, "UPDATE users SET deleted_at=now() WHERE id=$1 AND deleted_at IS NULL", userID)
 	if err != nil {
 		return err
 	}
 	if ct.RowsAffected() == 0 {
 		return errors.New("user not found")
 	}
+	if err := applyHandoffs(ctx, tx, handoffs); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *UserRepo) RestoreUser(ctx context.Context, userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return err
+	}
+	ct, err := r.pool.Exec(ctx, "UPDATE users SET deleted_at=NULL WHERE id=$1 AND deleted_at IS NOT NULL", userID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return errors.New("deleted user not found")
+	}
 	return nil
 }
 
+func (r *UserRepo) PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(ctx, tx)
+	if _, err := tx.Exec(ctx, `
+UPDATE pull_requests SET version = version + 1
+WHERE status = 'OPEN' AND id IN (
+    SELECT rv.pull_request_id FROM pull_request_reviewers rv
+    JOIN users u ON u.id = rv.user_id
+    WHERE u.deleted_at < $1)`, before); err != nil {
+		return 0, err
+	}
+	ct, err := tx.Exec(ctx, "DELETE FROM users WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), tx.Commit(ctx)
+}
+
 func (r *UserRepo) SetUserTeamByName(ctx context.Context, userID string, teamName *string) error {
 	if _, err := uuid.Parse(userID); err != nil {
 		return err
 	}
 	if teamName == nil {
-		_, err := r.pool.Exec(ctx, "UPDATE users SET team_id=NULL WHERE id=$1", userID)
-		return err
+		return r.SetUserTeamByID(ctx, userID, nil)
 	}
 	var teamID string
-	if err := r.pool.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1", *teamName).Scan(&teamID); err != nil {
+	if err := r.pool.QueryRow(ctx, "SELECT id::text FROM teams WHERE team_name=$1 AND deleted_at IS NULL", *teamName).Scan(&teamID); err != nil {
+		return domain.ErrTeamNotFound
+	}
+	return r.SetUserTeamByID(ctx, userID, &teamID)
+}
+
+func (r *UserRepo) SetUserTeamByID(ctx context.Context, userID string, teamID *string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return err
+	}
+	if teamID != nil {
+		if _, err := uuid.Parse(*teamID); err != nil {
+			return domain.ErrInvalidID
+		}
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+	var oldTeamID *string
+	if err := tx.QueryRow(ctx, "SELECT team_id::text FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE", userID).Scan(&oldTeamID); err != nil {
+		return errors.New("user not found")
+	}
+	if _, err := tx.Exec(ctx, "UPDATE users SET team_id=$1 WHERE id=$2", teamID, userID); err != nil {
+		return err
+	}
+	if err := syncPrimaryMembership(ctx, tx, userID, oldTeamID, teamID); err != nil {
 		return err
 	}
-	_, err := r.pool.Exec(ctx, "UPDATE users SET team_id=$1::uuid WHERE id=$2", teamID, userID)
-	return err
+	return tx.Commit(ctx)
+}
+
+func syncPrimaryMembership(ctx context.Context, tx pgx.Tx, userID string, oldTeamID, newTeamID *string) error {
+	if oldTeamID != nil && (newTeamID == nil || *oldTeamID != *newTeamID) {
+		if _, err := tx.Exec(ctx, "DELETE FROM team_memberships WHERE team_id=$1 AND user_id=$2", *oldTeamID, userID); err != nil {
+			return err
+		}
+	}
+	if newTeamID != nil {
+		if _, err := tx.Exec(ctx, "INSERT INTO team_memberships(team_id, user_id) VALUES($1,$2) ON CONFLICT DO NOTHING", *newTeamID, userID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
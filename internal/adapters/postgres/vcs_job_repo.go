@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type VCSJobRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewVCSJobRepo(pool *pgxpool.Pool) *VCSJobRepo {
+	return &VCSJobRepo{pool: pool}
+}
+
+func (r *VCSJobRepo) EnqueueJob(ctx context.Context, job domain.VCSJob) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO vcs_jobs(provider, repo, number, action, logins, attempts, last_error, next_run_at)
+VALUES($1,$2,$3,$4,$5,$6,$7,$8)`,
+		job.Ref.Provider, job.Ref.Repo, job.Ref.Number, string(job.Action), job.Logins, job.Attempts, nullIfEmpty(job.LastError), job.NextRunAt)
+	return err
+}
+
+func (r *VCSJobRepo) ClaimDueJobs(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSJob, error) {
+	rows, err := r.pool.Query(ctx, `
+UPDATE vcs_jobs SET next_run_at = now() + make_interval(secs => $2)
+WHERE id IN (
+    SELECT j.id FROM vcs_jobs j
+    WHERE j.status = 'pending' AND j.next_run_at <= now()
+      AND NOT EXISTS (
+        SELECT 1 FROM vcs_jobs e
+        WHERE e.provider = j.provider AND e.repo = j.repo AND e.number = j.number
+          AND e.status = 'pending' AND e.id < j.id
+      )
+    ORDER BY j.id
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, provider, repo, number, action, logins, attempts, coalesce(last_error, ''), next_run_at`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.VCSJob
+	for rows.Next() {
+		var j domain.VCSJob
+		var action string
+		if err := rows.Scan(&j.ID, &j.Ref.Provider, &j.Ref.Repo, &j.Ref.Number, &action, &j.Logins, &j.Attempts, &j.LastError, &j.NextRunAt); err != nil {
+			return nil, err
+		}
+		j.Action = domain.VCSJobAction(action)
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+func (r *VCSJobRepo) DeleteJob(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM vcs_jobs WHERE id=$1", id)
+	return err
+}
+
+func (r *VCSJobRepo) RescheduleJob(ctx context.Context, id int64, attempts int, nextRunAt time.Time, lastError string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE vcs_jobs SET attempts=$2, next_run_at=$3, last_error=$4 WHERE id=$1", id, attempts, nextRunAt, lastError)
+	return err
+}
+
+func (r *VCSJobRepo) FailJob(ctx context.Context, id int64, attempts int, lastError string) error {
+	_, err := r.pool.Exec(ctx, "UPDATE vcs_jobs SET status='failed', attempts=$2, last_error=$3 WHERE id=$1", id, attempts, lastError)
+	return err
+}
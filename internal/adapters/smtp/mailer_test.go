@@ -0,0 +1,121 @@
+package smtp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+type received struct {
+	from, to string
+	data     string
+}
+
+// serveSMTP answers one session with the minimal command set net/smtp needs.
+func serveSMTP(conn net.Conn, got chan<- received) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+	var msg received
+	_ = tp.PrintfLine("220 stand-in ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 stand-in")
+		case "MAIL":
+			msg.from = strings.TrimPrefix(line, "MAIL FROM:")
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			msg.to = strings.TrimPrefix(line, "RCPT TO:")
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			b, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			msg.data = string(b)
+			_ = tp.PrintfLine("250 queued")
+			got <- msg
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func listen(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestSendDeliversMultipartMessage(t *testing.T) {
+	got := make(chan received, 1)
+	addr := listen(t, func(c net.Conn) { serveSMTP(c, got) })
+
+	m := NewMailer(addr, "reviews@example.com", "", "")
+	if err := m.Send(context.Background(), "alice@example.com", "Ревью: 2 PR", "plain body", "<p>html body</p>"); err != nil {
+		t.Fatal(err)
+	}
+	msg := <-got
+	if msg.from != "<reviews@example.com>" || msg.to != "<alice@example.com>" {
+		t.Errorf("envelope from %q to %q", msg.from, msg.to)
+	}
+	for _, want := range []string{"To: alice@example.com", "Subject: =?utf-8?q?", "text/plain", "plain body", "text/html", "<p>html body</p>"} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message lacks %q:\n%s", want, msg.data)
+		}
+	}
+}
+
+func TestSendAbortsOnContextCancel(t *testing.T) {
+	closed := make(chan struct{})
+	addr := listen(t, func(c net.Conn) {
+		// Never greet; wait for the client to give up and hang up.
+		_, _ = bufio.NewReader(c).ReadByte()
+		_ = c.Close()
+		close(closed)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := NewMailer(addr, "reviews@example.com", "", "").Send(ctx, "alice@example.com", "s", "t", "h")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Send returned after %s", d)
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection left open after cancel")
+	}
+}
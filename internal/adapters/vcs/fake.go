@@ -0,0 +1,49 @@
+package vcs
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"sync"
+)
+
+type FakeCall struct {
+	Ref    domain.ExternalRef
+	Action domain.VCSJobAction
+	Logins []string
+}
+
+type FakeClient struct {
+	mu    sync.Mutex
+	calls []FakeCall
+	Err   error
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+func (c *FakeClient) RequestReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error {
+	return c.record(ctx, FakeCall{Ref: ref, Action: domain.VCSRequestReviewers, Logins: logins})
+}
+
+func (c *FakeClient) RemoveReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error {
+	return c.record(ctx, FakeCall{Ref: ref, Action: domain.VCSRemoveReviewers, Logins: logins})
+}
+
+func (c *FakeClient) Calls() []FakeCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]FakeCall(nil), c.calls...)
+}
+
+func (c *FakeClient) record(ctx context.Context, call FakeCall) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	c.calls = append(c.calls, call)
+	logging.FromContext(ctx).Info("fake vcs call", "provider", call.Ref.Provider, "repo", call.Ref.Repo, "number", call.Ref.Number, "action", call.Action, "logins", call.Logins)
+	return nil
+}
@@ -0,0 +1,67 @@
+package vcs
+
+import (
+	"AvitoTestTask/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultGitHubAPI = "https://api.github.com"
+
+type GitHubClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitHubClient(baseURL, token string) *GitHubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPI
+	}
+	return &GitHubClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *GitHubClient) RequestReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error {
+	return c.reviewers(ctx, http.MethodPost, ref, logins)
+}
+
+func (c *GitHubClient) RemoveReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error {
+	return c.reviewers(ctx, http.MethodDelete, ref, logins)
+}
+
+func (c *GitHubClient) reviewers(ctx context.Context, method string, ref domain.ExternalRef, logins []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return err
+	}
+	url := c.baseURL + "/repos/" + ref.Repo + "/pulls/" + strconv.Itoa(ref.Number) + "/requested_reviewers"
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("github %s %s: %s: %s", method, url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
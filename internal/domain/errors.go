@@ -3,8 +3,32 @@ package domain
 import "errors"
 
 var (
-	ErrInvalidID           = errors.New("invalid id (must be uuid string)")
-	ErrPRMerged            = errors.New("pr is merged")
-	ErrReviewerNotAssigned = errors.New("reviewer is not assigned")
-	ErrNoCandidate         = errors.New("no replacement candidate available")
+	ErrInvalidID            = errors.New("invalid id (must be uuid string)")
+	ErrPRMerged             = errors.New("pr is merged")
+	ErrPRClosed             = errors.New("pr is closed")
+	ErrPRNotFound           = errors.New("pr not found")
+	ErrReviewerNotAssigned  = errors.New("reviewer is not assigned")
+	ErrNoCandidate          = errors.New("no replacement candidate available")
+	ErrAlreadyAssigned      = errors.New("user is already assigned as reviewer")
+	ErrAuthorReviewer       = errors.New("pr author cannot review their own pr")
+	ErrReviewerInactive     = errors.New("user cannot review: inactive user or membership")
+	ErrReviewerLimit        = errors.New("team reviewer limit reached")
+	ErrVersionConflict      = errors.New("pr was modified concurrently")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrNoTeam               = errors.New("user has no team")
+	ErrTeamNameTaken        = errors.New("team name is already in use")
+	ErrNotMember            = errors.New("user is not a member of the team")
+	ErrRepositoryNotFound   = errors.New("repository not found")
+	ErrInvalidPolicy        = errors.New("invalid reviewer policy")
+	ErrUnknownIdentity      = errors.New("external identity is not linked to a user")
+	ErrTeamMismatch         = errors.New("team_id and team_name refer to different teams")
+	ErrChatNotConfigured    = errors.New("team has no chat webhook configured")
+	ErrSubscriptionNotFound = errors.New("email subscription not found")
+	ErrImportInvalid        = errors.New("import contains invalid rows")
+	ErrImportDeletedUser    = errors.New("user is deleted; restore it before importing")
+	ErrImportDeactivation   = errors.New("import cannot deactivate an active user; use /user/update")
+	ErrRestoreNotEmpty      = errors.New("restore requires an empty database")
+	ErrSnapshotVersion      = errors.New("unsupported snapshot format version")
+	ErrUnauthorized         = errors.New("missing or invalid api token")
+	ErrForbidden            = errors.New("insufficient permissions")
 )
@@ -0,0 +1,33 @@
+package domain
+
+type ImportRow struct {
+	Row      int
+	TeamName string
+	UserID   string
+	Username string
+	IsActive bool
+	Role     MemberRole
+}
+
+const (
+	ImportCreated   = "created"
+	ImportUpdated   = "updated"
+	ImportUnchanged = "unchanged"
+	ImportValid     = "valid"
+	ImportError     = "error"
+)
+
+type ImportRowResult struct {
+	Row      int    `json:"row"`
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun       bool              `json:"dry_run"`
+	Applied      bool              `json:"applied"`
+	TeamsCreated int               `json:"teams_created"`
+	Rows         []ImportRowResult `json:"rows"`
+}
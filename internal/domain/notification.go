@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+type ChatMode string
+
+const (
+	ChatImmediate ChatMode = "immediate"
+	ChatDigest    ChatMode = "digest"
+)
+
+func (m ChatMode) Valid() bool {
+	return m == ChatImmediate || m == ChatDigest
+}
+
+type TeamChatSettings struct {
+	TeamID     string
+	WebhookURL string
+	Mode       ChatMode
+}
+
+type ChatRecipient struct {
+	UserID   string
+	Username string
+	OptedOut bool
+}
+
+type EmailSubscription struct {
+	UserID     string
+	Email      string
+	Enabled    bool
+	LastSentAt *time.Time
+}
+
+type DigestRecipient struct {
+	UserID   string
+	Username string
+	Email    string
+}
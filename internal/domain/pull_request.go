@@ -1,33 +1,74 @@
 package domain
 
-import "time"
+import (
+	"math/rand/v2"
+	"sort"
+	"time"
+)
 
 type PRStatus string
 
 const (
 	StatusOpen   PRStatus = "OPEN"
 	StatusMerged PRStatus = "MERGED"
+	StatusClosed PRStatus = "CLOSED"
+)
+
+// NoReviewerLimit disables the reviewer count check in Validate; 0 is a real cap.
+const NoReviewerLimit = -1
+
+type ReviewHandoff struct {
+	PRID          string
+	PRName        string
+	OldReviewerID string
+	NewReviewerID string
+	Version       int
+}
+
+type ReviewerChangeAction string
+
+const (
+	ReviewerAdded   ReviewerChangeAction = "add"
+	ReviewerRemoved ReviewerChangeAction = "remove"
 )
 
+type ReviewerChange struct {
+	PRID         string
+	UserID       string
+	Action       ReviewerChangeAction
+	Forced       bool
+	ActorTokenID string
+}
+
 type PullRequest struct {
-	ID                string     `json:"pull_request_id"`
-	Name              string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            PRStatus   `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ID                string       `json:"pull_request_id"`
+	Name              string       `json:"pull_request_name"`
+	AuthorID          string       `json:"author_id"`
+	TeamID            string       `json:"team_id,omitempty"`
+	RepositoryID      string       `json:"repository_id,omitempty"`
+	Status            PRStatus     `json:"status"`
+	AssignedReviewers []string     `json:"assigned_reviewers"`
+	External          *ExternalRef `json:"external,omitempty"`
+	Version           int          `json:"version"`
+	CreatedAt         *time.Time   `json:"createdAt,omitempty"`
+	MergedAt          *time.Time   `json:"mergedAt,omitempty"`
 }
 
-func (pr *PullRequest) AssignReviewersFromMembers(members []TeamMember, limit int) {
-	candidates := make([]string, 0, 2)
+func (pr *PullRequest) AssignReviewers(members []TeamMember, policy ReviewerPolicy, openReviews map[string]int) {
+	candidates := make([]string, 0, len(members))
 	for _, member := range members {
-		if member.IsActive && member.UserID != pr.AuthorID {
+		if member.CanReview() && member.UserID != pr.AuthorID {
 			candidates = append(candidates, member.UserID)
 		}
-		if len(candidates) == limit {
-			break
-		}
+	}
+	switch policy.Strategy {
+	case StrategyRandom:
+		rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	case StrategyLeastLoaded:
+		sort.SliceStable(candidates, func(i, j int) bool { return openReviews[candidates[i]] < openReviews[candidates[j]] })
+	}
+	if len(candidates) > policy.ReviewerCount {
+		candidates = candidates[:policy.ReviewerCount]
 	}
 	pr.AssignedReviewers = candidates
 }
@@ -36,6 +77,12 @@ func (pr *PullRequest) Reassign(oldReviewer string, newReviewer string) error {
 	if pr.Status == StatusMerged {
 		return ErrPRMerged
 	}
+	if pr.Status == StatusClosed {
+		return ErrPRClosed
+	}
+	if oldReviewer == newReviewer {
+		return ErrAlreadyAssigned
+	}
 	for i, candidate := range pr.AssignedReviewers {
 		if candidate == oldReviewer {
 			pr.AssignedReviewers[i] = newReviewer
@@ -45,6 +92,66 @@ func (pr *PullRequest) Reassign(oldReviewer string, newReviewer string) error {
 	return ErrReviewerNotAssigned
 }
 
+func (pr *PullRequest) AddReviewer(reviewer string) error {
+	if pr.Status == StatusMerged {
+		return ErrPRMerged
+	}
+	if pr.Status == StatusClosed {
+		return ErrPRClosed
+	}
+	pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer)
+	return nil
+}
+
+// Validate checks the reviewer list after a write. Only the reviewers in added
+// must be active members of team: reviewers already on the PR are not
+// re-checked, so one who went inactive or left the team never blocks the write
+// that removes or replaces them.
+func (pr *PullRequest) Validate(team *Team, limit int, added ...string) error {
+	seen := make(map[string]struct{}, len(pr.AssignedReviewers))
+	for _, r := range pr.AssignedReviewers {
+		if r == pr.AuthorID {
+			return ErrAuthorReviewer
+		}
+		if _, dup := seen[r]; dup {
+			return ErrAlreadyAssigned
+		}
+		seen[r] = struct{}{}
+	}
+	for _, id := range added {
+		if team == nil {
+			return ErrNotMember
+		}
+		m, ok := team.Member(id)
+		if !ok {
+			return ErrNotMember
+		}
+		if !m.CanReview() {
+			return ErrReviewerInactive
+		}
+	}
+	if limit != NoReviewerLimit && len(pr.AssignedReviewers) > limit {
+		return ErrReviewerLimit
+	}
+	return nil
+}
+
+func (pr *PullRequest) RemoveReviewer(reviewer string) error {
+	if pr.Status == StatusMerged {
+		return ErrPRMerged
+	}
+	if pr.Status == StatusClosed {
+		return ErrPRClosed
+	}
+	for i, r := range pr.AssignedReviewers {
+		if r == reviewer {
+			pr.AssignedReviewers = append(pr.AssignedReviewers[:i:i], pr.AssignedReviewers[i+1:]...)
+			return nil
+		}
+	}
+	return ErrReviewerNotAssigned
+}
+
 func (pr *PullRequest) Merge() {
 	if pr.Status == StatusMerged {
 		return
@@ -53,3 +160,29 @@ func (pr *PullRequest) Merge() {
 	now := time.Now().UTC()
 	pr.MergedAt = &now
 }
+
+func (pr *PullRequest) Close() error {
+	switch pr.Status {
+	case StatusMerged:
+		return ErrPRMerged
+	case StatusClosed:
+		return nil
+	}
+	pr.Status = StatusClosed
+	return nil
+}
+
+func (pr *PullRequest) Reopen() error {
+	if pr.Status == StatusMerged {
+		return ErrPRMerged
+	}
+	pr.Status = StatusOpen
+	return nil
+}
+
+func (pr *PullRequest) CheckVersion(expected int) error {
+	if expected != 0 && expected != pr.Version {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+func TestValidateLimit(t *testing.T) {
+	team := &Team{Members: []TeamMember{
+		{UserID: "u1", IsActive: true, MembershipActive: true, Role: MemberRoleMember},
+		{UserID: "u2", IsActive: true, MembershipActive: true, Role: MemberRoleMember},
+	}}
+	for _, tc := range []struct {
+		name      string
+		reviewers []string
+		limit     int
+		want      error
+	}{
+		{"zero cap rejects any reviewer", []string{"u1"}, 0, ErrReviewerLimit},
+		{"zero cap allows none", nil, 0, nil},
+		{"at cap", []string{"u1", "u2"}, 2, nil},
+		{"over cap", []string{"u1", "u2"}, 1, ErrReviewerLimit},
+		{"no limit", []string{"u1", "u2"}, NoReviewerLimit, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pr := &PullRequest{AuthorID: "author", AssignedReviewers: tc.reviewers}
+			if err := pr.Validate(team, tc.limit, tc.reviewers...); !errors.Is(err, tc.want) {
+				t.Fatalf("Validate = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateKeepsExistingReviewers(t *testing.T) {
+	team := &Team{Members: []TeamMember{
+		{UserID: "active", IsActive: true, MembershipActive: true, Role: MemberRoleMember},
+		{UserID: "inactive", IsActive: false, MembershipActive: true, Role: MemberRoleMember},
+		{UserID: "observer", IsActive: true, MembershipActive: true, Role: MemberRoleObserver},
+	}}
+	pr := &PullRequest{AuthorID: "author", AssignedReviewers: []string{"inactive", "left-team", "observer", "active"}}
+	if err := pr.RemoveReviewer("active"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Validate(team, NoReviewerLimit); err != nil {
+		t.Fatalf("existing reviewers blocked the write: %v", err)
+	}
+	for _, id := range []string{"inactive", "observer"} {
+		if err := pr.Validate(team, NoReviewerLimit, id); !errors.Is(err, ErrReviewerInactive) {
+			t.Errorf("adding %s: %v, want ErrReviewerInactive", id, err)
+		}
+	}
+	if err := pr.Validate(team, NoReviewerLimit, "left-team"); !errors.Is(err, ErrNotMember) {
+		t.Errorf("adding a non-member: %v, want ErrNotMember", err)
+	}
+}
+
+// validateCase draws reviewers from a small id pool so that authors,
+// duplicates and non-members show up often.
+type validateCase struct {
+	Team      *Team
+	Author    string
+	Reviewers []string
+	Added     []string
+	Limit     int
+}
+
+var validateIDs = []string{"u0", "u1", "u2", "u3", "u4", "u5"}
+
+func (validateCase) Generate(r *rand.Rand, _ int) reflect.Value {
+	c := validateCase{Team: &Team{}, Author: validateIDs[r.Intn(len(validateIDs))], Limit: r.Intn(5) - 1}
+	for _, id := range validateIDs {
+		switch r.Intn(4) {
+		case 0:
+		case 1:
+			c.Team.Members = append(c.Team.Members, TeamMember{UserID: id, Role: MemberRoleMember})
+		default:
+			c.Team.Members = append(c.Team.Members, TeamMember{UserID: id, IsActive: true, MembershipActive: true, Role: MemberRoleMember})
+		}
+	}
+	for i := r.Intn(5); i > 0; i-- {
+		c.Reviewers = append(c.Reviewers, validateIDs[r.Intn(len(validateIDs))])
+	}
+	for _, id := range c.Reviewers {
+		if r.Intn(2) == 0 {
+			c.Added = append(c.Added, id)
+		}
+	}
+	return reflect.ValueOf(c)
+}
+
+func (c validateCase) validate() error {
+	pr := &PullRequest{AuthorID: c.Author, AssignedReviewers: c.Reviewers}
+	return pr.Validate(c.Team, c.Limit, c.Added...)
+}
+
+func TestValidateProperties(t *testing.T) {
+	properties := map[string]func(validateCase) bool{
+		"author is never a reviewer": func(c validateCase) bool {
+			return !slices.Contains(c.Reviewers, c.Author) || c.validate() != nil
+		},
+		"duplicates are rejected": func(c validateCase) bool {
+			return len(slices.Compact(slices.Sorted(slices.Values(c.Reviewers)))) == len(c.Reviewers) || c.validate() != nil
+		},
+		"added reviewers can review": func(c validateCase) bool {
+			if c.validate() != nil {
+				return true
+			}
+			for _, id := range c.Added {
+				if m, ok := c.Team.Member(id); !ok || !m.CanReview() {
+					return false
+				}
+			}
+			return true
+		},
+		"limit applies unless disabled": func(c validateCase) bool {
+			over := c.Limit != NoReviewerLimit && len(c.Reviewers) > c.Limit
+			return !over || c.validate() != nil
+		},
+		"existing reviewers are not checked against the team": func(c validateCase) bool {
+			if c.validate() != nil {
+				return true
+			}
+			c.Team, c.Added = nil, nil
+			return c.validate() == nil
+		},
+		"no limit never reports the limit": func(c validateCase) bool {
+			c.Limit = NoReviewerLimit
+			return !errors.Is(c.validate(), ErrReviewerLimit)
+		},
+		"removing a reviewer keeps a valid list valid": func(c validateCase) bool {
+			if c.validate() != nil || len(c.Reviewers) == 0 {
+				return true
+			}
+			pr := &PullRequest{AuthorID: c.Author, AssignedReviewers: slices.Clone(c.Reviewers)}
+			if err := pr.RemoveReviewer(c.Reviewers[0]); err != nil {
+				return false
+			}
+			return pr.Validate(nil, c.Limit) == nil
+		},
+	}
+	for name, prop := range properties {
+		t.Run(name, func(t *testing.T) {
+			if err := quick.Check(prop, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
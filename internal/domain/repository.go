@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type ReviewerStrategy string
+
+const (
+	StrategyFirstAvailable ReviewerStrategy = "first_available"
+	StrategyRandom         ReviewerStrategy = "random"
+	StrategyLeastLoaded    ReviewerStrategy = "least_loaded"
+)
+
+func (s ReviewerStrategy) Valid() bool {
+	return s == StrategyFirstAvailable || s == StrategyRandom || s == StrategyLeastLoaded
+}
+
+type ReviewerPolicy struct {
+	ReviewerCount int              `json:"reviewer_count"`
+	Strategy      ReviewerStrategy `json:"reviewer_strategy"`
+}
+
+func DefaultReviewerPolicy() ReviewerPolicy {
+	return ReviewerPolicy{ReviewerCount: 2, Strategy: StrategyFirstAvailable}
+}
+
+type CodeRepository struct {
+	ID             string         `json:"repository_id"`
+	Name           string         `json:"name"`
+	VCSURL         string         `json:"vcs_url"`
+	OwningTeamID   *string        `json:"owning_team_id,omitempty"`
+	OwningTeamName *string        `json:"owning_team,omitempty"`
+	Policy         ReviewerPolicy `json:"reviewer_policy"`
+	CreatedAt      *time.Time     `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "time"
+
+const SnapshotFormatVersion = 2
+
+type Snapshot struct {
+	FormatVersion      int                         `json:"format_version"`
+	SchemaVersion      int                         `json:"schema_version"`
+	ExportedAt         time.Time                   `json:"exported_at"`
+	Teams              []SnapshotTeam              `json:"teams"`
+	Users              []SnapshotUser              `json:"users"`
+	Memberships        []SnapshotMembership        `json:"memberships"`
+	Repositories       []SnapshotRepository        `json:"repositories"`
+	PullRequests       []SnapshotPR                `json:"pull_requests"`
+	ExternalIdentities []SnapshotIdentity          `json:"external_identities"`
+	ChatSettings       []SnapshotChatSettings      `json:"chat_settings"`
+	EmailSubscriptions []SnapshotEmailSubscription `json:"email_subscriptions"`
+}
+
+type SnapshotTeam struct {
+	ID        string     `json:"team_id"`
+	TeamName  string     `json:"team_name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+type SnapshotUser struct {
+	ID         string     `json:"user_id"`
+	Username   string     `json:"username"`
+	TeamID     *string    `json:"team_id,omitempty"`
+	IsActive   bool       `json:"is_active"`
+	ChatOptOut bool       `json:"chat_opt_out,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+type SnapshotMembership struct {
+	TeamID    string     `json:"team_id"`
+	UserID    string     `json:"user_id"`
+	Role      MemberRole `json:"role"`
+	IsActive  bool       `json:"is_active"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type SnapshotRepository struct {
+	ID               string           `json:"repository_id"`
+	Name             string           `json:"name"`
+	VCSURL           string           `json:"vcs_url"`
+	OwningTeamID     *string          `json:"owning_team_id,omitempty"`
+	ReviewerCount    int              `json:"reviewer_count"`
+	ReviewerStrategy ReviewerStrategy `json:"reviewer_strategy"`
+	CreatedAt        *time.Time       `json:"created_at,omitempty"`
+}
+
+type SnapshotPR struct {
+	ID              string                   `json:"pull_request_id"`
+	Name            string                   `json:"pull_request_name"`
+	AuthorID        *string                  `json:"author_id,omitempty"`
+	TeamID          *string                  `json:"team_id,omitempty"`
+	RepositoryID    *string                  `json:"repository_id,omitempty"`
+	Status          PRStatus                 `json:"status"`
+	Version         int                      `json:"version"`
+	External        *ExternalRef             `json:"external,omitempty"`
+	CreatedAt       *time.Time               `json:"created_at,omitempty"`
+	MergedAt        *time.Time               `json:"merged_at,omitempty"`
+	Reviewers       []SnapshotReviewer       `json:"reviewers"`
+	ReviewerChanges []SnapshotReviewerChange `json:"reviewer_changes"`
+}
+
+type SnapshotReviewer struct {
+	UserID     string     `json:"user_id"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+}
+
+type SnapshotReviewerChange struct {
+	UserID    string               `json:"user_id"`
+	Action    ReviewerChangeAction `json:"action"`
+	Forced    bool                 `json:"forced,omitempty"`
+	CreatedAt *time.Time           `json:"created_at,omitempty"`
+}
+
+type SnapshotIdentity struct {
+	Provider  string     `json:"provider"`
+	Login     string     `json:"external_login"`
+	UserID    string     `json:"user_id"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type SnapshotEmailSubscription struct {
+	UserID     string     `json:"user_id"`
+	Email      string     `json:"email"`
+	Enabled    bool       `json:"enabled"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+type SnapshotChatSettings struct {
+	TeamID     string   `json:"team_id"`
+	WebhookURL string   `json:"webhook_url"`
+	Mode       ChatMode `json:"mode"`
+}
@@ -1,12 +1,40 @@
 package domain
 
+type MemberRole string
+
+const (
+	MemberRoleMember   MemberRole = "member"
+	MemberRoleLead     MemberRole = "lead"
+	MemberRoleObserver MemberRole = "observer"
+)
+
+func (r MemberRole) Valid() bool {
+	return r == MemberRoleMember || r == MemberRoleLead || r == MemberRoleObserver
+}
+
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID           string     `json:"user_id"`
+	Username         string     `json:"username"`
+	IsActive         bool       `json:"is_active"`
+	Role             MemberRole `json:"role"`
+	MembershipActive bool       `json:"membership_active"`
+}
+
+func (m TeamMember) CanReview() bool {
+	return m.IsActive && m.MembershipActive && m.Role != MemberRoleObserver
 }
 
 type Team struct {
+	ID       string       `json:"team_id"`
 	TeamName string       `json:"team_name"`
 	Members  []TeamMember `json:"members"`
 }
+
+func (t *Team) Member(userID string) (TeamMember, bool) {
+	for _, m := range t.Members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+	return TeamMember{}, false
+}
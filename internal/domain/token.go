@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+func (r Role) Valid() bool {
+	return r == RoleAdmin || r == RoleUser
+}
+
+type APIToken struct {
+	ID        string     `json:"token_id"`
+	Name      string     `json:"name"`
+	Role      Role       `json:"role"`
+	UserID    *string    `json:"user_id,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+func (t *APIToken) IsAdmin() bool {
+	return t.Role == RoleAdmin
+}
@@ -3,6 +3,7 @@ package domain
 type User struct {
 	ID       string  `json:"user_id"`
 	Username string  `json:"username"`
+	TeamID   *string `json:"team_id"`
 	TeamName *string `json:"team_name"`
 	IsActive bool    `json:"is_active"`
 }
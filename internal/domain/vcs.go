@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+type ExternalRef struct {
+	Provider string `json:"provider"`
+	Repo     string `json:"repo"`
+	Number   int    `json:"number"`
+}
+
+type ExternalIdentity struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
+
+type PREventAction string
+
+const (
+	PREventOpened   PREventAction = "opened"
+	PREventReopened PREventAction = "reopened"
+	PREventClosed   PREventAction = "closed"
+	PREventMerged   PREventAction = "merged"
+)
+
+type PullRequestEvent struct {
+	Action      PREventAction
+	Ref         ExternalRef
+	Title       string
+	AuthorLogin string
+	Draft       bool
+}
+
+type VCSJobAction string
+
+const (
+	VCSRequestReviewers VCSJobAction = "request_reviewers"
+	VCSRemoveReviewers  VCSJobAction = "remove_reviewers"
+)
+
+type VCSJob struct {
+	ID        int64
+	Ref       ExternalRef
+	Action    VCSJobAction
+	Logins    []string
+	Attempts  int
+	LastError string
+	NextRunAt time.Time
+}
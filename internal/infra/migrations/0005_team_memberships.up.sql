@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS team_memberships (
+    team_id uuid NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role text NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead', 'observer')),
+    is_active boolean NOT NULL DEFAULT true,
+    created_at timestamptz DEFAULT now(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_memberships_user ON team_memberships(user_id);
+
+INSERT INTO team_memberships(team_id, user_id)
+SELECT team_id, id FROM users WHERE team_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS team_id uuid REFERENCES teams(id) ON DELETE SET NULL;
+
+UPDATE pull_requests pr SET team_id = u.team_id
+FROM users u
+WHERE pr.author_id = u.id AND pr.team_id IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository_id;
+DROP TABLE IF EXISTS repositories;
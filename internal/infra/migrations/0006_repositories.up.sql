@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL UNIQUE,
+    vcs_url text NOT NULL DEFAULT '',
+    owning_team_id uuid REFERENCES teams(id) ON DELETE SET NULL,
+    reviewer_count integer NOT NULL DEFAULT 2 CHECK (reviewer_count >= 0),
+    reviewer_strategy text NOT NULL DEFAULT 'first_available'
+        CHECK (reviewer_strategy IN ('first_available', 'random', 'least_loaded')),
+    created_at timestamptz DEFAULT now()
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository_id uuid REFERENCES repositories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository ON pull_requests(repository_id);
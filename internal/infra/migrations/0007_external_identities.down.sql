@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pull_requests_external;
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS external_provider,
+    DROP COLUMN IF EXISTS external_repo,
+    DROP COLUMN IF EXISTS external_number;
+DROP TABLE IF EXISTS external_identities;
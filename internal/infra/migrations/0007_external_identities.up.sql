@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS external_identities (
+    provider text NOT NULL,
+    external_login text NOT NULL,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamptz DEFAULT now(),
+    PRIMARY KEY (provider, external_login)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_identities_user ON external_identities(provider, user_id);
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS external_provider text,
+    ADD COLUMN IF NOT EXISTS external_repo text,
+    ADD COLUMN IF NOT EXISTS external_number integer;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pull_requests_external
+    ON pull_requests(external_provider, external_repo, external_number)
+    WHERE external_provider IS NOT NULL;
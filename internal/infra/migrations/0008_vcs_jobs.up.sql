@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS vcs_jobs (
+    id bigserial PRIMARY KEY,
+    provider text NOT NULL,
+    repo text NOT NULL,
+    number integer NOT NULL,
+    action text NOT NULL CHECK (action IN ('request_reviewers', 'remove_reviewers')),
+    logins text[] NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'failed')),
+    attempts integer NOT NULL DEFAULT 0,
+    last_error text,
+    next_run_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vcs_jobs_due ON vcs_jobs(next_run_at) WHERE status = 'pending';
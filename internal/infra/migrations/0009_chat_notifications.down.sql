@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS chat_digest_items;
+ALTER TABLE users DROP COLUMN IF EXISTS chat_opt_out;
+DROP TABLE IF EXISTS team_chat_settings;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS team_chat_settings (
+    team_id uuid PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    webhook_url text NOT NULL,
+    mode text NOT NULL DEFAULT 'immediate' CHECK (mode IN ('immediate', 'digest')),
+    updated_at timestamptz DEFAULT now()
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS chat_opt_out boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS chat_digest_items (
+    id bigserial PRIMARY KEY,
+    team_id uuid NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    message text NOT NULL,
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_digest_items_team ON chat_digest_items(team_id);
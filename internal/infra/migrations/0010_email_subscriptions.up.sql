@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS email_subscriptions (
+    user_id uuid PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email text NOT NULL,
+    enabled boolean NOT NULL DEFAULT true,
+    last_sent_at timestamptz,
+    created_at timestamptz DEFAULT now()
+);
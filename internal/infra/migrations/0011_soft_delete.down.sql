@@ -0,0 +1,11 @@
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+UPDATE users SET team_id = NULL WHERE team_id IN (SELECT id FROM teams WHERE deleted_at IS NOT NULL);
+DELETE FROM teams WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_teams_deleted;
+DROP INDEX IF EXISTS idx_users_deleted;
+DROP INDEX IF EXISTS idx_teams_team_name_live;
+ALTER TABLE teams ADD CONSTRAINT teams_team_name_key UNIQUE (team_name);
+
+ALTER TABLE teams DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
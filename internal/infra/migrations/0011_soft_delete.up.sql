@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_team_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_team_name_live ON teams(team_name) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_teams_deleted ON teams(deleted_at) WHERE deleted_at IS NOT NULL;
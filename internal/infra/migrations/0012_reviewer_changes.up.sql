@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pull_request_reviewer_changes (
+    id bigserial PRIMARY KEY,
+    pull_request_id text NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL,
+    action text NOT NULL CHECK (action IN ('add', 'remove')),
+    forced boolean NOT NULL DEFAULT false,
+    actor_token_id uuid REFERENCES api_tokens(id) ON DELETE SET NULL,
+    created_at timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_changes_pr ON pull_request_reviewer_changes(pull_request_id, created_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_vcs_jobs_pr;
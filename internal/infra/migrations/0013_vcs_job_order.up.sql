@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_vcs_jobs_pr ON vcs_jobs(provider, repo, number, id) WHERE status = 'pending';
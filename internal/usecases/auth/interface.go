@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type Repository interface {
+	CreateToken(ctx context.Context, t domain.APIToken, tokenHash string) (*domain.APIToken, error)
+	GetTokenByHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	ListTokens(ctx context.Context) ([]domain.APIToken, error)
+	RevokeToken(ctx context.Context, tokenID string) error
+}
+
+type Service interface {
+	IssueToken(ctx context.Context, name string, role domain.Role, userID *string) (string, *domain.APIToken, error)
+	Authenticate(ctx context.Context, rawToken string) (*domain.APIToken, error)
+	ListTokens(ctx context.Context) ([]domain.APIToken, error)
+	RevokeToken(ctx context.Context, tokenID string) error
+}
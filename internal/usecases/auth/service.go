@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const tokenPrefix = "atk_"
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repository: r}
+}
+
+func (s *service) IssueToken(ctx context.Context, name string, role domain.Role, userID *string) (string, *domain.APIToken, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, errors.New("token name is required")
+	}
+	if !role.Valid() {
+		return "", nil, errors.New("invalid role")
+	}
+	if userID != nil {
+		if _, err := uuid.Parse(*userID); err != nil {
+			return "", nil, errors.New("invalid user_id")
+		}
+	}
+	if role == domain.RoleUser && userID == nil {
+		return "", nil, errors.New("user tokens must be bound to a user_id")
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	raw := tokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	t, err := s.repository.CreateToken(ctx, domain.APIToken{Name: name, Role: role, UserID: userID}, hashToken(raw))
+	if err != nil {
+		return "", nil, err
+	}
+	logging.FromContext(ctx).Info("api token issued", "token_id", t.ID, "role", t.Role)
+	return raw, t, nil
+}
+
+func (s *service) Authenticate(ctx context.Context, rawToken string) (*domain.APIToken, error) {
+	if !strings.HasPrefix(rawToken, tokenPrefix) {
+		return nil, domain.ErrUnauthorized
+	}
+	t, err := s.repository.GetTokenByHash(ctx, hashToken(rawToken))
+	if err != nil {
+		logging.FromContext(ctx).Debug("token lookup failed", "err", err)
+		return nil, domain.ErrUnauthorized
+	}
+	return t, nil
+}
+
+func (s *service) ListTokens(ctx context.Context) ([]domain.APIToken, error) {
+	return s.repository.ListTokens(ctx)
+}
+
+func (s *service) RevokeToken(ctx context.Context, tokenID string) error {
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return errors.New("invalid token_id")
+	}
+	return s.repository.RevokeToken(ctx, tokenID)
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,17 @@
+package backup
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type Repository interface {
+	ExportSnapshot(ctx context.Context) (*domain.Snapshot, error)
+	RestoreSnapshot(ctx context.Context, snap *domain.Snapshot) error
+	SchemaVersion(ctx context.Context) (int, error)
+}
+
+type Service interface {
+	Export(ctx context.Context) (*domain.Snapshot, error)
+	Restore(ctx context.Context, snap *domain.Snapshot) error
+}
@@ -0,0 +1,43 @@
+package backup
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"fmt"
+)
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repository: r}
+}
+
+func (s *service) Export(ctx context.Context) (*domain.Snapshot, error) {
+	snap, err := s.repository.ExportSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("snapshot exported", "teams", len(snap.Teams), "users", len(snap.Users), "pull_requests", len(snap.PullRequests))
+	return snap, nil
+}
+
+func (s *service) Restore(ctx context.Context, snap *domain.Snapshot) error {
+	if snap.FormatVersion != domain.SnapshotFormatVersion {
+		return fmt.Errorf("%w: %d (expected %d)", domain.ErrSnapshotVersion, snap.FormatVersion, domain.SnapshotFormatVersion)
+	}
+	schema, err := s.repository.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if snap.SchemaVersion != schema {
+		return fmt.Errorf("%w: schema %d (database is at %d)", domain.ErrSnapshotVersion, snap.SchemaVersion, schema)
+	}
+	if err := s.repository.RestoreSnapshot(ctx, snap); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("snapshot restored", "teams", len(snap.Teams), "users", len(snap.Users), "pull_requests", len(snap.PullRequests), "exported_at", snap.ExportedAt)
+	return nil
+}
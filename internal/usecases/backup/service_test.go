@@ -0,0 +1,52 @@
+package backup
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+type memRepo struct {
+	Repository
+	schema   int
+	restored *domain.Snapshot
+}
+
+func (r *memRepo) SchemaVersion(context.Context) (int, error) {
+	return r.schema, nil
+}
+
+func (r *memRepo) RestoreSnapshot(_ context.Context, snap *domain.Snapshot) error {
+	r.restored = snap
+	return nil
+}
+
+func TestRestoreChecksVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  int
+		schema  int
+		wantErr bool
+	}{
+		{"matching", domain.SnapshotFormatVersion, 13, false},
+		{"old format", domain.SnapshotFormatVersion - 1, 13, true},
+		{"older schema", domain.SnapshotFormatVersion, 12, true},
+		{"newer schema", domain.SnapshotFormatVersion, 14, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memRepo{schema: 13}
+			err := NewService(repo).Restore(context.Background(), &domain.Snapshot{FormatVersion: tt.format, SchemaVersion: tt.schema})
+			if !tt.wantErr {
+				if err != nil || repo.restored == nil {
+					t.Fatalf("err = %v, restored = %v", err, repo.restored != nil)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrSnapshotVersion) || repo.restored != nil {
+				t.Fatalf("err = %v, restored = %v", err, repo.restored != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package bulkimport
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"io"
+)
+
+type Repository interface {
+	ApplyImport(ctx context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportReport, error)
+}
+
+type Service interface {
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*domain.ImportReport, error)
+}
@@ -0,0 +1,141 @@
+package bulkimport
+
+import (
+	"AvitoTestTask/internal/domain"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+type document struct {
+	Teams []struct {
+		TeamName string `json:"team_name" yaml:"team_name"`
+		Members  []struct {
+			UserID   string  `json:"user_id" yaml:"user_id"`
+			Username string  `json:"username" yaml:"username"`
+			IsActive *bool   `json:"is_active" yaml:"is_active"`
+			Role     *string `json:"role" yaml:"role"`
+		} `json:"members" yaml:"members"`
+	} `json:"teams" yaml:"teams"`
+}
+
+func FormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return ""
+}
+
+func Parse(format string, r io.Reader) ([]domain.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		var doc document
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		return doc.rows(), nil
+	case FormatYAML:
+		var doc document
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+		return doc.rows(), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q (csv|json|yaml)", format)
+}
+
+func (d document) rows() []domain.ImportRow {
+	var out []domain.ImportRow
+	for _, t := range d.Teams {
+		if len(t.Members) == 0 {
+			out = append(out, domain.ImportRow{Row: len(out) + 1, TeamName: t.TeamName})
+			continue
+		}
+		for _, m := range t.Members {
+			row := domain.ImportRow{Row: len(out) + 1, TeamName: t.TeamName, UserID: m.UserID, Username: m.Username, IsActive: true, Role: domain.MemberRoleMember}
+			if m.IsActive != nil {
+				row.IsActive = *m.IsActive
+			}
+			if m.Role != nil {
+				row.Role = domain.MemberRole(*m.Role)
+			}
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func parseCSV(r io.Reader) ([]domain.ImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := cols["team_name"]; !ok {
+		return nil, errors.New("parse csv: team_name column is required")
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	var out []domain.ImportRow
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse csv: %w", err)
+		}
+		row := domain.ImportRow{
+			Row:      line,
+			TeamName: get(rec, "team_name"),
+			UserID:   get(rec, "user_id"),
+			Username: get(rec, "username"),
+			IsActive: true,
+			Role:     domain.MemberRoleMember,
+		}
+		if v := get(rec, "is_active"); v != "" {
+			active, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("parse csv line %d: invalid is_active %q", line, v)
+			}
+			row.IsActive = active
+		}
+		if v := get(rec, "role"); v != "" {
+			row.Role = domain.MemberRole(v)
+		}
+		out = append(out, row)
+	}
+}
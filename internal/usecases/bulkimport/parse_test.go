@@ -0,0 +1,104 @@
+package bulkimport
+
+import (
+	"AvitoTestTask/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	importUserA = "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"
+	importUserB = "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+)
+
+func TestParse(t *testing.T) {
+	want := []domain.ImportRow{
+		{Row: 2, TeamName: "backend", UserID: importUserA, Username: "alice", IsActive: true, Role: domain.MemberRoleLead},
+		{Row: 3, TeamName: "backend", UserID: importUserB, Username: "bob", IsActive: false, Role: domain.MemberRoleMember},
+		{Row: 4, TeamName: "empty", IsActive: true, Role: domain.MemberRoleMember},
+	}
+	docRows := []domain.ImportRow{want[0], want[1], {TeamName: "empty"}}
+	for i := range docRows {
+		docRows[i].Row = i + 1
+	}
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   []domain.ImportRow
+	}{
+		{"csv", FormatCSV, "team_name,user_id,username,is_active,role\n" +
+			"backend," + importUserA + ",alice,,lead\n" +
+			"backend, " + importUserB + ", bob ,false,\n" +
+			"empty,,,,\n", want},
+		{"csv columns in any order", FormatCSV, "Role,Username,User_ID,Team_Name\n" +
+			"lead,alice," + importUserA + ",backend\n" +
+			"member,bob," + importUserB + ",backend\n" +
+			",,,empty\n", []domain.ImportRow{want[0], {Row: 3, TeamName: "backend", UserID: importUserB, Username: "bob", IsActive: true, Role: domain.MemberRoleMember}, want[2]}},
+		{"json", FormatJSON, `{"teams":[
+			{"team_name":"backend","members":[
+				{"user_id":"` + importUserA + `","username":"alice","role":"lead"},
+				{"user_id":"` + importUserB + `","username":"bob","is_active":false}]},
+			{"team_name":"empty"}]}`, docRows},
+		{"yaml", FormatYAML, `teams:
+  - team_name: backend
+    members:
+      - {user_id: ` + importUserA + `, username: alice, role: lead}
+      - {user_id: ` + importUserB + `, username: bob, is_active: false}
+  - team_name: empty
+`, docRows},
+		{"empty yaml", FormatYAML, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.format, strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   string
+	}{
+		{"unknown format", "xml", "<teams/>", "unsupported format"},
+		{"csv without header", FormatCSV, "", "parse csv header"},
+		{"csv without team_name", FormatCSV, "user_id,username\n", "team_name column is required"},
+		{"csv bad is_active", FormatCSV, "team_name,is_active\nbackend,maybe\n", `line 2: invalid is_active "maybe"`},
+		{"csv ragged row", FormatCSV, "team_name,user_id\nbackend\n", "parse csv"},
+		{"json unknown field", FormatJSON, `{"teams":[{"team_name":"backend","lead":"alice"}]}`, "unknown field"},
+		{"json malformed", FormatJSON, `{"teams":[`, "parse json"},
+		{"yaml unknown field", FormatYAML, "teams:\n  - team_name: backend\n    lead: alice\n", "parse yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	for name, want := range map[string]string{
+		"teams.csv":  FormatCSV,
+		"TEAMS.JSON": FormatJSON,
+		"teams.yml":  FormatYAML,
+		"teams.yaml": FormatYAML,
+		"teams.txt":  "",
+	} {
+		if got := FormatFromName(name); got != want {
+			t.Errorf("FormatFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
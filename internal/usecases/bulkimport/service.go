@@ -0,0 +1,90 @@
+package bulkimport
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repository: r}
+}
+
+func (s *service) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*domain.ImportReport, error) {
+	rows, err := Parse(format, r)
+	if err != nil {
+		return nil, err
+	}
+	if report := validate(rows, dryRun); report != nil {
+		return report, domain.ErrImportInvalid
+	}
+	report, err := s.repository.ApplyImport(ctx, rows, dryRun)
+	if err != nil {
+		return report, err
+	}
+	logging.FromContext(ctx).Info("bulk import", "rows", len(rows), "teams_created", report.TeamsCreated, "dry_run", dryRun, "applied", report.Applied)
+	return report, nil
+}
+
+func validate(rows []domain.ImportRow, dryRun bool) *domain.ImportReport {
+	type userKey struct {
+		username string
+		active   bool
+	}
+	users := make(map[string]userKey)
+	seen := make(map[string]int)
+	report := &domain.ImportReport{DryRun: dryRun, Rows: make([]domain.ImportRowResult, 0, len(rows))}
+	failed := false
+	for i := range rows {
+		row := &rows[i]
+		row.TeamName = strings.TrimSpace(row.TeamName)
+		row.Username = strings.TrimSpace(row.Username)
+		res := domain.ImportRowResult{Row: row.Row, TeamName: row.TeamName, UserID: row.UserID, Status: domain.ImportValid}
+		var msg string
+		switch {
+		case row.TeamName == "":
+			msg = "team_name is required"
+		case row.UserID == "" && row.Username == "":
+		case row.UserID == "":
+			msg = "user_id is required"
+		case uuid.Validate(row.UserID) != nil:
+			msg = domain.ErrInvalidID.Error()
+		case row.Username == "":
+			msg = "username is required"
+		case !row.Role.Valid():
+			msg = "invalid role " + string(row.Role)
+		}
+		if msg == "" && row.UserID != "" {
+			key := row.TeamName + "\x00" + row.UserID
+			if prev, ok := seen[key]; ok {
+				msg = "duplicate of row " + strconv.Itoa(prev)
+			} else {
+				seen[key] = row.Row
+			}
+			u := userKey{row.Username, row.IsActive}
+			if prev, ok := users[row.UserID]; ok && prev != u {
+				msg = "conflicting username or is_active for the same user_id"
+			} else {
+				users[row.UserID] = u
+			}
+		}
+		if msg != "" {
+			res.Status, res.Error = domain.ImportError, msg
+			failed = true
+		}
+		report.Rows = append(report.Rows, res)
+	}
+	if !failed {
+		return nil
+	}
+	return report
+}
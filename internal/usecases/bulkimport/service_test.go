@@ -0,0 +1,64 @@
+package bulkimport
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type recordingRepo struct {
+	rows []domain.ImportRow
+}
+
+func (r *recordingRepo) ApplyImport(_ context.Context, rows []domain.ImportRow, dryRun bool) (*domain.ImportReport, error) {
+	r.rows = rows
+	return &domain.ImportReport{DryRun: dryRun, Applied: !dryRun}, nil
+}
+
+func TestImportValidationReport(t *testing.T) {
+	const header = "team_name,user_id,username,is_active,role\n"
+	tests := []struct {
+		name   string
+		rows   string
+		errors map[int]string
+	}{
+		{"valid", "backend," + importUserA + ",alice,,\nempty,,,,\n", nil},
+		{"missing team", "," + importUserA + ",alice,,\n", map[int]string{2: "team_name is required"}},
+		{"missing user_id", "backend,,alice,,\n", map[int]string{2: "user_id is required"}},
+		{"bad user_id", "backend,42,alice,,\n", map[int]string{2: domain.ErrInvalidID.Error()}},
+		{"missing username", "backend," + importUserA + ",,,\n", map[int]string{2: "username is required"}},
+		{"bad role", "backend," + importUserA + ",alice,,owner\n", map[int]string{2: "invalid role owner"}},
+		{"duplicate membership", "backend," + importUserA + ",alice,,\nbackend," + importUserA + ",alice,,lead\n", map[int]string{3: "duplicate of row 2"}},
+		{"conflicting user", "backend," + importUserA + ",alice,,\nfrontend," + importUserA + ",alice,false,\n", map[int]string{3: "conflicting username or is_active for the same user_id"}},
+		{"every bad row reported", ",,,,\nbackend," + importUserB + ",bob,,\nbackend,x,bob,,\n", map[int]string{2: "team_name is required", 4: domain.ErrInvalidID.Error()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &recordingRepo{}
+			report, err := NewService(repo).Import(context.Background(), FormatCSV, strings.NewReader(header+tt.rows), true)
+			if tt.errors == nil {
+				if err != nil || repo.rows == nil {
+					t.Fatalf("err = %v, applied rows = %v", err, repo.rows)
+				}
+				return
+			}
+			if !errors.Is(err, domain.ErrImportInvalid) || report == nil {
+				t.Fatalf("err = %v, report = %+v", err, report)
+			}
+			if repo.rows != nil {
+				t.Fatal("invalid import reached the repository")
+			}
+			for _, res := range report.Rows {
+				want, bad := tt.errors[res.Row]
+				if bad && (res.Status != domain.ImportError || res.Error != want) {
+					t.Errorf("row %d = %s %q, want error %q", res.Row, res.Status, res.Error, want)
+				}
+				if !bad && res.Status != domain.ImportValid {
+					t.Errorf("row %d = %s %q, want valid", res.Row, res.Status, res.Error)
+				}
+			}
+		})
+	}
+}
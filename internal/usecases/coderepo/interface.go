@@ -0,0 +1,27 @@
+package coderepo
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type Repository interface {
+	CreateRepository(ctx context.Context, cr domain.CodeRepository) (string, error)
+	GetRepositoryByName(ctx context.Context, name string) (*domain.CodeRepository, error)
+	GetRepositoryByID(ctx context.Context, id string) (*domain.CodeRepository, error)
+	ListRepositories(ctx context.Context) ([]domain.CodeRepository, error)
+	UpdateRepository(ctx context.Context, cr domain.CodeRepository) error
+	DeleteRepository(ctx context.Context, name string) error
+}
+
+type TeamRepository interface {
+	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type Service interface {
+	CreateRepository(ctx context.Context, cr domain.CodeRepository) (*domain.CodeRepository, error)
+	GetRepository(ctx context.Context, name string) (*domain.CodeRepository, error)
+	ListRepositories(ctx context.Context) ([]domain.CodeRepository, error)
+	UpdateRepository(ctx context.Context, name string, cr domain.CodeRepository) (*domain.CodeRepository, error)
+	DeleteRepository(ctx context.Context, name string) error
+}
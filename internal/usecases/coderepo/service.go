@@ -0,0 +1,88 @@
+package coderepo
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+type service struct {
+	repository Repository
+	teamRepo   TeamRepository
+}
+
+func NewService(r Repository, t TeamRepository) Service {
+	return &service{repository: r, teamRepo: t}
+}
+
+func (s *service) CreateRepository(ctx context.Context, cr domain.CodeRepository) (*domain.CodeRepository, error) {
+	if err := s.prepare(ctx, &cr); err != nil {
+		return nil, err
+	}
+	if _, err := s.repository.CreateRepository(ctx, cr); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("repository created", "name", cr.Name)
+	return s.repository.GetRepositoryByName(ctx, cr.Name)
+}
+
+func (s *service) GetRepository(ctx context.Context, name string) (*domain.CodeRepository, error) {
+	return s.repository.GetRepositoryByName(ctx, name)
+}
+
+func (s *service) ListRepositories(ctx context.Context) ([]domain.CodeRepository, error) {
+	return s.repository.ListRepositories(ctx)
+}
+
+func (s *service) UpdateRepository(ctx context.Context, name string, cr domain.CodeRepository) (*domain.CodeRepository, error) {
+	existing, err := s.repository.GetRepositoryByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	cr.ID = existing.ID
+	if err := s.prepare(ctx, &cr); err != nil {
+		return nil, err
+	}
+	if err := s.repository.UpdateRepository(ctx, cr); err != nil {
+		return nil, err
+	}
+	return s.repository.GetRepositoryByID(ctx, cr.ID)
+}
+
+func (s *service) DeleteRepository(ctx context.Context, name string) error {
+	if err := s.repository.DeleteRepository(ctx, name); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("repository deleted", "name", name)
+	return nil
+}
+
+func (s *service) prepare(ctx context.Context, cr *domain.CodeRepository) error {
+	cr.Name = strings.TrimSpace(cr.Name)
+	if cr.Name == "" {
+		return errors.New("repository name is required")
+	}
+	if cr.VCSURL != "" {
+		if u, err := url.Parse(cr.VCSURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("invalid vcs_url")
+		}
+	}
+	if cr.Policy.Strategy == "" {
+		cr.Policy.Strategy = domain.StrategyFirstAvailable
+	}
+	if !cr.Policy.Strategy.Valid() || cr.Policy.ReviewerCount < 0 {
+		return domain.ErrInvalidPolicy
+	}
+	cr.OwningTeamID = nil
+	if cr.OwningTeamName != nil {
+		team, err := s.teamRepo.GetTeamByName(ctx, *cr.OwningTeamName)
+		if err != nil {
+			return err
+		}
+		cr.OwningTeamID = &team.ID
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package coderepo
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/coderepo")}
+}
+
+func (s *tracedService) CreateRepository(ctx context.Context, cr domain.CodeRepository) (out *domain.CodeRepository, err error) {
+	ctx, span := s.tracer.Start(ctx, "coderepo.CreateRepository", trace.WithAttributes(attribute.String("repository.name", cr.Name)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateRepository(ctx, cr)
+}
+
+func (s *tracedService) GetRepository(ctx context.Context, name string) (out *domain.CodeRepository, err error) {
+	ctx, span := s.tracer.Start(ctx, "coderepo.GetRepository", trace.WithAttributes(attribute.String("repository.name", name)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetRepository(ctx, name)
+}
+
+func (s *tracedService) ListRepositories(ctx context.Context) (out []domain.CodeRepository, err error) {
+	ctx, span := s.tracer.Start(ctx, "coderepo.ListRepositories")
+	defer func() { tracing.End(span, err) }()
+	return s.next.ListRepositories(ctx)
+}
+
+func (s *tracedService) UpdateRepository(ctx context.Context, name string, cr domain.CodeRepository) (out *domain.CodeRepository, err error) {
+	ctx, span := s.tracer.Start(ctx, "coderepo.UpdateRepository", trace.WithAttributes(attribute.String("repository.name", name)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateRepository(ctx, name, cr)
+}
+
+func (s *tracedService) DeleteRepository(ctx context.Context, name string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "coderepo.DeleteRepository", trace.WithAttributes(attribute.String("repository.name", name)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteRepository(ctx, name)
+}
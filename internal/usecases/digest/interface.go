@@ -0,0 +1,30 @@
+package digest
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"time"
+)
+
+type Repository interface {
+	UpsertSubscription(ctx context.Context, sub domain.EmailSubscription) error
+	GetSubscription(ctx context.Context, userID string) (*domain.EmailSubscription, error)
+	DeleteSubscription(ctx context.Context, userID string) error
+	DueDigestRecipients(ctx context.Context, sentBefore time.Time) ([]domain.DigestRecipient, error)
+	MarkDigestSent(ctx context.Context, userID string, at time.Time) error
+}
+
+type PRRepository interface {
+	GetPRsForReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, to, subject, textBody, htmlBody string) error
+}
+
+type Service interface {
+	Subscribe(ctx context.Context, sub domain.EmailSubscription) error
+	GetSubscription(ctx context.Context, userID string) (*domain.EmailSubscription, error)
+	Unsubscribe(ctx context.Context, userID string) error
+	SendDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,125 @@
+package digest
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/mail"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var (
+	textTmpl = texttemplate.Must(texttemplate.ParseFS(templatesFS, "templates/digest.txt.tmpl"))
+	htmlTmpl = htmltemplate.Must(htmltemplate.ParseFS(templatesFS, "templates/digest.html.tmpl"))
+)
+
+type digestData struct {
+	Username     string
+	PullRequests []domain.PullRequest
+}
+
+type service struct {
+	repository Repository
+	prs        PRRepository
+	mailer     Mailer
+	period     time.Duration
+}
+
+func NewService(r Repository, prs PRRepository, mailer Mailer, period time.Duration) Service {
+	return &service{repository: r, prs: prs, mailer: mailer, period: period}
+}
+
+func (s *service) Subscribe(ctx context.Context, sub domain.EmailSubscription) error {
+	if _, err := uuid.Parse(sub.UserID); err != nil {
+		return domain.ErrInvalidID
+	}
+	addr, err := mail.ParseAddress(sub.Email)
+	if err != nil {
+		return errors.New("invalid email")
+	}
+	sub.Email = addr.Address
+	return s.repository.UpsertSubscription(ctx, sub)
+}
+
+func (s *service) GetSubscription(ctx context.Context, userID string) (*domain.EmailSubscription, error) {
+	return s.repository.GetSubscription(ctx, userID)
+}
+
+func (s *service) Unsubscribe(ctx context.Context, userID string) error {
+	return s.repository.DeleteSubscription(ctx, userID)
+}
+
+func (s *service) SendDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	recipients, err := s.repository.DueDigestRecipients(ctx, now.Add(-s.period))
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, rc := range recipients {
+		log := logging.FromContext(ctx).With("user_id", rc.UserID)
+		prs, err := s.prs.GetPRsForReviewer(ctx, rc.UserID)
+		if err != nil {
+			return sent, err
+		}
+		data := digestData{Username: rc.Username}
+		for _, pr := range prs {
+			if pr.Status == domain.StatusOpen {
+				data.PullRequests = append(data.PullRequests, pr)
+			}
+		}
+		if len(data.PullRequests) > 0 {
+			text, html, err := render(data)
+			if err != nil {
+				return sent, err
+			}
+			subject := fmt.Sprintf("%d pull request(s) waiting for your review", len(data.PullRequests))
+			if err := s.mailer.Send(ctx, rc.Email, subject, text, html); err != nil {
+				log.Warn("email digest failed", "err", err)
+				continue
+			}
+			sent++
+		}
+		if err := s.repository.MarkDigestSent(ctx, rc.UserID, now); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func Run(ctx context.Context, svc Service, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := svc.SendDue(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("send email digests", "err", err)
+			}
+		}
+	}
+}
+
+func render(data digestData) (string, string, error) {
+	var text, html bytes.Buffer
+	if err := textTmpl.Execute(&text, data); err != nil {
+		return "", "", err
+	}
+	if err := htmlTmpl.Execute(&html, data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
@@ -0,0 +1,46 @@
+package digest
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/digest")}
+}
+
+func (s *tracedService) Subscribe(ctx context.Context, sub domain.EmailSubscription) (err error) {
+	ctx, span := s.tracer.Start(ctx, "digest.Subscribe", trace.WithAttributes(attribute.String("user.id", sub.UserID), attribute.Bool("digest.enabled", sub.Enabled)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Subscribe(ctx, sub)
+}
+
+func (s *tracedService) GetSubscription(ctx context.Context, userID string) (out *domain.EmailSubscription, err error) {
+	ctx, span := s.tracer.Start(ctx, "digest.GetSubscription", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetSubscription(ctx, userID)
+}
+
+func (s *tracedService) Unsubscribe(ctx context.Context, userID string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "digest.Unsubscribe", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Unsubscribe(ctx, userID)
+}
+
+func (s *tracedService) SendDue(ctx context.Context) (n int, err error) {
+	ctx, span := s.tracer.Start(ctx, "digest.SendDue")
+	defer func() {
+		span.SetAttributes(attribute.Int("digest.sent", n))
+		tracing.End(span, err)
+	}()
+	return s.next.SendDue(ctx)
+}
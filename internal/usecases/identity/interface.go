@@ -0,0 +1,22 @@
+package identity
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type Repository interface {
+	LinkIdentity(ctx context.Context, id domain.ExternalIdentity) error
+	UnlinkIdentity(ctx context.Context, provider, login string) error
+	GetUserIDByLogin(ctx context.Context, provider, login string) (string, error)
+	GetLoginsByUserIDs(ctx context.Context, provider string, userIDs []string) (map[string]string, error)
+	ListIdentitiesForUser(ctx context.Context, userID string) ([]domain.ExternalIdentity, error)
+}
+
+type Service interface {
+	Link(ctx context.Context, id domain.ExternalIdentity) error
+	Unlink(ctx context.Context, provider, login string) error
+	Resolve(ctx context.Context, provider, login string) (string, error)
+	LoginsFor(ctx context.Context, provider string, userIDs []string) (map[string]string, error)
+	ListForUser(ctx context.Context, userID string) ([]domain.ExternalIdentity, error)
+}
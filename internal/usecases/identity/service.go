@@ -0,0 +1,65 @@
+package identity
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type service struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &service{repository: r}
+}
+
+func (s *service) Link(ctx context.Context, id domain.ExternalIdentity) error {
+	id.Provider = strings.ToLower(strings.TrimSpace(id.Provider))
+	id.Login = normalizeLogin(id.Login)
+	if id.Provider != domain.ProviderGitHub && id.Provider != domain.ProviderGitLab {
+		return errors.New("unknown provider")
+	}
+	if id.Login == "" {
+		return errors.New("login is required")
+	}
+	if _, err := uuid.Parse(id.UserID); err != nil {
+		return errors.New("invalid user_id")
+	}
+	if err := s.repository.LinkIdentity(ctx, id); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("external identity linked", "provider", id.Provider, "login", id.Login, "user_id", id.UserID)
+	return nil
+}
+
+func (s *service) Unlink(ctx context.Context, provider, login string) error {
+	return s.repository.UnlinkIdentity(ctx, strings.ToLower(provider), normalizeLogin(login))
+}
+
+func (s *service) Resolve(ctx context.Context, provider, login string) (string, error) {
+	uid, err := s.repository.GetUserIDByLogin(ctx, strings.ToLower(provider), normalizeLogin(login))
+	if err != nil {
+		return "", domain.ErrUnknownIdentity
+	}
+	return uid, nil
+}
+
+func (s *service) LoginsFor(ctx context.Context, provider string, userIDs []string) (map[string]string, error) {
+	return s.repository.GetLoginsByUserIDs(ctx, strings.ToLower(provider), userIDs)
+}
+
+func (s *service) ListForUser(ctx context.Context, userID string) ([]domain.ExternalIdentity, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user_id")
+	}
+	return s.repository.ListIdentitiesForUser(ctx, userID)
+}
+
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
@@ -0,0 +1,49 @@
+package identity
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/identity")}
+}
+
+func (s *tracedService) Link(ctx context.Context, id domain.ExternalIdentity) (err error) {
+	ctx, span := s.tracer.Start(ctx, "identity.Link", trace.WithAttributes(attribute.String("vcs.provider", id.Provider), attribute.String("user.id", id.UserID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Link(ctx, id)
+}
+
+func (s *tracedService) Unlink(ctx context.Context, provider, login string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "identity.Unlink", trace.WithAttributes(attribute.String("vcs.provider", provider)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Unlink(ctx, provider, login)
+}
+
+func (s *tracedService) Resolve(ctx context.Context, provider, login string) (out string, err error) {
+	ctx, span := s.tracer.Start(ctx, "identity.Resolve", trace.WithAttributes(attribute.String("vcs.provider", provider)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Resolve(ctx, provider, login)
+}
+
+func (s *tracedService) LoginsFor(ctx context.Context, provider string, userIDs []string) (out map[string]string, err error) {
+	ctx, span := s.tracer.Start(ctx, "identity.LoginsFor", trace.WithAttributes(attribute.String("vcs.provider", provider), attribute.Int("user.count", len(userIDs))))
+	defer func() { tracing.End(span, err) }()
+	return s.next.LoginsFor(ctx, provider, userIDs)
+}
+
+func (s *tracedService) ListForUser(ctx context.Context, userID string) (out []domain.ExternalIdentity, err error) {
+	ctx, span := s.tracer.Start(ctx, "identity.ListForUser", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ListForUser(ctx, userID)
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type Repository interface {
+	GetTeamChatSettings(ctx context.Context, teamID string) (*domain.TeamChatSettings, error)
+	SetTeamChatSettings(ctx context.Context, settings domain.TeamChatSettings) error
+	DeleteTeamChatSettings(ctx context.Context, teamID string) error
+	GetChatRecipients(ctx context.Context, userIDs []string) (map[string]domain.ChatRecipient, error)
+	SetUserChatOptOut(ctx context.Context, userID string, optOut bool) error
+	AddDigestItem(ctx context.Context, teamID, message string) error
+	TakeDigestItems(ctx context.Context) (map[string][]string, error)
+}
+
+type TeamRepository interface {
+	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type Sender interface {
+	Send(ctx context.Context, webhookURL, text string) error
+}
+
+type Service interface {
+	ReviewersAssigned(ctx context.Context, pr *domain.PullRequest, reviewers []string)
+	ReviewerReassigned(ctx context.Context, pr *domain.PullRequest, oldUserID, newUserID string)
+	PRMerged(ctx context.Context, pr *domain.PullRequest)
+
+	ConfigureTeam(ctx context.Context, teamName, webhookURL string, mode domain.ChatMode) error
+	DisableTeam(ctx context.Context, teamName string) error
+	SetOptOut(ctx context.Context, userID string, optOut bool) error
+	FlushDigests(ctx context.Context) (int, error)
+	Deliver(ctx context.Context)
+}
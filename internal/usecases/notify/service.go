@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const outboxSize = 256
+
+type chatMessage struct {
+	webhookURL string
+	text       string
+	log        *slog.Logger
+}
+
+type service struct {
+	repository Repository
+	teams      TeamRepository
+	sender     Sender
+	outbox     chan chatMessage
+}
+
+func NewService(r Repository, t TeamRepository, sender Sender) Service {
+	return &service{repository: r, teams: t, sender: sender, outbox: make(chan chatMessage, outboxSize)}
+}
+
+func (s *service) ReviewersAssigned(ctx context.Context, pr *domain.PullRequest, reviewers []string) {
+	s.notify(ctx, pr, reviewers, func(rcpt map[string]domain.ChatRecipient) string {
+		if len(reviewers) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("Review requested on %s: %s", prTitle(pr), strings.Join(mentionAll(reviewers, rcpt), ", "))
+	})
+}
+
+func (s *service) ReviewerReassigned(ctx context.Context, pr *domain.PullRequest, oldUserID, newUserID string) {
+	s.notify(ctx, pr, []string{oldUserID, newUserID}, func(rcpt map[string]domain.ChatRecipient) string {
+		return fmt.Sprintf("Reviewer on %s changed: %s → %s", prTitle(pr), displayName(oldUserID, rcpt), mention(newUserID, rcpt))
+	})
+}
+
+func (s *service) PRMerged(ctx context.Context, pr *domain.PullRequest) {
+	s.notify(ctx, pr, pr.AssignedReviewers, func(rcpt map[string]domain.ChatRecipient) string {
+		msg := fmt.Sprintf("%s merged", prTitle(pr))
+		if len(pr.AssignedReviewers) > 0 {
+			msg += ", thanks " + strings.Join(mentionAll(pr.AssignedReviewers, rcpt), ", ")
+		}
+		return msg
+	})
+}
+
+func (s *service) notify(ctx context.Context, pr *domain.PullRequest, userIDs []string, format func(map[string]domain.ChatRecipient) string) {
+	if pr.TeamID == "" {
+		return
+	}
+	log := logging.FromContext(ctx).With("pr_id", pr.ID, "team_id", pr.TeamID)
+	settings, err := s.repository.GetTeamChatSettings(ctx, pr.TeamID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrChatNotConfigured) {
+			log.Error("load chat settings", "err", err)
+		}
+		return
+	}
+	rcpt, err := s.repository.GetChatRecipients(ctx, userIDs)
+	if err != nil {
+		log.Error("load chat recipients", "err", err)
+		return
+	}
+	msg := format(rcpt)
+	if msg == "" {
+		return
+	}
+	if settings.Mode == domain.ChatDigest {
+		if err := s.repository.AddDigestItem(ctx, pr.TeamID, msg); err != nil {
+			log.Error("queue chat digest item", "err", err)
+		}
+		return
+	}
+	select {
+	case s.outbox <- chatMessage{webhookURL: settings.WebhookURL, text: msg, log: log}:
+	default:
+		log.Warn("chat outbox full, dropping notification")
+	}
+}
+
+func (s *service) Deliver(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			if n := len(s.outbox); n > 0 {
+				logging.FromContext(ctx).Warn("chat outbox not drained", "pending", n)
+			}
+			return
+		case m := <-s.outbox:
+			if err := s.sender.Send(ctx, m.webhookURL, m.text); err != nil && ctx.Err() == nil {
+				m.log.Warn("chat notification failed", "err", err)
+			}
+		}
+	}
+}
+
+func (s *service) ConfigureTeam(ctx context.Context, teamName, webhookURL string, mode domain.ChatMode) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid webhook_url")
+	}
+	if mode == "" {
+		mode = domain.ChatImmediate
+	}
+	if !mode.Valid() {
+		return errors.New("invalid mode")
+	}
+	team, err := s.teams.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.SetTeamChatSettings(ctx, domain.TeamChatSettings{TeamID: team.ID, WebhookURL: webhookURL, Mode: mode}); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("team chat configured", "team_id", team.ID, "mode", mode)
+	return nil
+}
+
+func (s *service) DisableTeam(ctx context.Context, teamName string) error {
+	team, err := s.teams.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	return s.repository.DeleteTeamChatSettings(ctx, team.ID)
+}
+
+func (s *service) SetOptOut(ctx context.Context, userID string, optOut bool) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return domain.ErrInvalidID
+	}
+	return s.repository.SetUserChatOptOut(ctx, userID, optOut)
+}
+
+func (s *service) FlushDigests(ctx context.Context) (int, error) {
+	items, err := s.repository.TakeDigestItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for teamID, msgs := range items {
+		log := logging.FromContext(ctx).With("team_id", teamID)
+		settings, err := s.repository.GetTeamChatSettings(ctx, teamID)
+		if errors.Is(err, domain.ErrChatNotConfigured) {
+			continue
+		}
+		if err == nil {
+			text := fmt.Sprintf("Review digest (%d updates):\n• %s", len(msgs), strings.Join(msgs, "\n• "))
+			if err = s.sender.Send(ctx, settings.WebhookURL, text); err == nil {
+				sent++
+				continue
+			}
+		}
+		log.Warn("chat digest failed, requeueing", "err", err)
+		for _, m := range msgs {
+			if err := s.repository.AddDigestItem(ctx, teamID, m); err != nil {
+				return sent, err
+			}
+		}
+	}
+	return sent, nil
+}
+
+func Run(ctx context.Context, svc Service, interval time.Duration) {
+	go svc.Deliver(ctx)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := svc.FlushDigests(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("flush chat digests", "err", err)
+			}
+		}
+	}
+}
+
+func prTitle(pr *domain.PullRequest) string {
+	if pr.Name == "" {
+		return "`" + pr.ID + "`"
+	}
+	return "*" + pr.Name + "* (`" + pr.ID + "`)"
+}
+
+func mentionAll(userIDs []string, rcpt map[string]domain.ChatRecipient) []string {
+	out := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		out = append(out, mention(id, rcpt))
+	}
+	return out
+}
+
+func mention(userID string, rcpt map[string]domain.ChatRecipient) string {
+	if r, ok := rcpt[userID]; ok && !r.OptedOut {
+		return "@" + r.Username
+	}
+	return displayName(userID, rcpt)
+}
+
+func displayName(userID string, rcpt map[string]domain.ChatRecipient) string {
+	if r, ok := rcpt[userID]; ok {
+		return r.Username
+	}
+	return userID
+}
@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"AvitoTestTask/internal/adapters/chat"
+	"AvitoTestTask/internal/domain"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type memChatRepo struct {
+	Repository
+	settings domain.TeamChatSettings
+	rcpt     map[string]domain.ChatRecipient
+}
+
+func (r memChatRepo) GetTeamChatSettings(_ context.Context, teamID string) (*domain.TeamChatSettings, error) {
+	if teamID != r.settings.TeamID {
+		return nil, domain.ErrChatNotConfigured
+	}
+	s := r.settings
+	return &s, nil
+}
+
+func (r memChatRepo) GetChatRecipients(_ context.Context, userIDs []string) (map[string]domain.ChatRecipient, error) {
+	out := map[string]domain.ChatRecipient{}
+	for _, id := range userIDs {
+		if rc, ok := r.rcpt[id]; ok {
+			out[id] = rc
+		}
+	}
+	return out, nil
+}
+
+// chatStandIn is an incoming-webhook endpoint that records posted messages.
+func chatStandIn(t *testing.T, delay time.Duration) (string, <-chan string) {
+	t.Helper()
+	got := make(chan string, 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		time.Sleep(delay)
+		got <- body.Text
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, got
+}
+
+func newTestService(t *testing.T, delay time.Duration) (Service, <-chan string) {
+	t.Helper()
+	url, got := chatStandIn(t, delay)
+	repo := memChatRepo{
+		settings: domain.TeamChatSettings{TeamID: "team-1", WebhookURL: url, Mode: domain.ChatImmediate},
+		rcpt: map[string]domain.ChatRecipient{
+			"u1": {UserID: "u1", Username: "alice"},
+			"u2": {UserID: "u2", Username: "bob", OptedOut: true},
+		},
+	}
+	svc := NewService(repo, nil, chat.NewWebhookSender())
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go svc.Deliver(ctx)
+	return svc, got
+}
+
+func receive(t *testing.T, got <-chan string) string {
+	t.Helper()
+	select {
+	case msg := <-got:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no chat message delivered")
+		return ""
+	}
+}
+
+func TestNotifyDoesNotWaitForWebhook(t *testing.T) {
+	svc, got := newTestService(t, 300*time.Millisecond)
+	pr := &domain.PullRequest{ID: "pr-1", Name: "Add cache", TeamID: "team-1"}
+
+	start := time.Now()
+	svc.ReviewersAssigned(context.Background(), pr, []string{"u1"})
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Fatalf("ReviewersAssigned blocked for %s", d)
+	}
+	if msg, want := receive(t, got), "Review requested on *Add cache* (`pr-1`): @alice"; msg != want {
+		t.Errorf("message = %q, want %q", msg, want)
+	}
+}
+
+func TestOptedOutUsersAreNamedNotMentioned(t *testing.T) {
+	svc, got := newTestService(t, 0)
+	pr := &domain.PullRequest{ID: "pr-1", TeamID: "team-1", AssignedReviewers: []string{"u1", "u2"}}
+	ctx := context.Background()
+
+	svc.ReviewerReassigned(ctx, pr, "u1", "u2")
+	if msg, want := receive(t, got), "Reviewer on `pr-1` changed: alice → bob"; msg != want {
+		t.Errorf("reassign message = %q, want %q", msg, want)
+	}
+	svc.ReviewersAssigned(ctx, pr, []string{"u2"})
+	if msg, want := receive(t, got), "Review requested on `pr-1`: bob"; msg != want {
+		t.Errorf("assign message = %q, want %q", msg, want)
+	}
+	svc.PRMerged(ctx, pr)
+	if msg, want := receive(t, got), "`pr-1` merged, thanks @alice, bob"; msg != want {
+		t.Errorf("merge message = %q, want %q", msg, want)
+	}
+}
+
+func TestNotifySkipsTeamsWithoutChat(t *testing.T) {
+	svc, got := newTestService(t, 0)
+	svc.ReviewersAssigned(context.Background(), &domain.PullRequest{ID: "pr-1", TeamID: "team-2"}, []string{"u1"})
+	select {
+	case msg := <-got:
+		t.Fatalf("unexpected message %q", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
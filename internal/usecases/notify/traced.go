@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/notify")}
+}
+
+func (s *tracedService) ReviewersAssigned(ctx context.Context, pr *domain.PullRequest, reviewers []string) {
+	ctx, span := s.tracer.Start(ctx, "notify.ReviewersAssigned", trace.WithAttributes(attribute.String("pr.id", pr.ID)))
+	defer span.End()
+	s.next.ReviewersAssigned(ctx, pr, reviewers)
+}
+
+func (s *tracedService) ReviewerReassigned(ctx context.Context, pr *domain.PullRequest, oldUserID, newUserID string) {
+	ctx, span := s.tracer.Start(ctx, "notify.ReviewerReassigned", trace.WithAttributes(attribute.String("pr.id", pr.ID)))
+	defer span.End()
+	s.next.ReviewerReassigned(ctx, pr, oldUserID, newUserID)
+}
+
+func (s *tracedService) PRMerged(ctx context.Context, pr *domain.PullRequest) {
+	ctx, span := s.tracer.Start(ctx, "notify.PRMerged", trace.WithAttributes(attribute.String("pr.id", pr.ID)))
+	defer span.End()
+	s.next.PRMerged(ctx, pr)
+}
+
+func (s *tracedService) ConfigureTeam(ctx context.Context, teamName, webhookURL string, mode domain.ChatMode) (err error) {
+	ctx, span := s.tracer.Start(ctx, "notify.ConfigureTeam", trace.WithAttributes(attribute.String("team.name", teamName), attribute.String("chat.mode", string(mode))))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ConfigureTeam(ctx, teamName, webhookURL, mode)
+}
+
+func (s *tracedService) DisableTeam(ctx context.Context, teamName string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "notify.DisableTeam", trace.WithAttributes(attribute.String("team.name", teamName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DisableTeam(ctx, teamName)
+}
+
+func (s *tracedService) SetOptOut(ctx context.Context, userID string, optOut bool) (err error) {
+	ctx, span := s.tracer.Start(ctx, "notify.SetOptOut", trace.WithAttributes(attribute.String("user.id", userID), attribute.Bool("chat.opt_out", optOut)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.SetOptOut(ctx, userID, optOut)
+}
+
+func (s *tracedService) FlushDigests(ctx context.Context) (n int, err error) {
+	ctx, span := s.tracer.Start(ctx, "notify.FlushDigests")
+	defer func() {
+		span.SetAttributes(attribute.Int("chat.digests_sent", n))
+		tracing.End(span, err)
+	}()
+	return s.next.FlushDigests(ctx)
+}
+
+func (s *tracedService) Deliver(ctx context.Context) {
+	s.next.Deliver(ctx)
+}
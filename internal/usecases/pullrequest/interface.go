@@ -7,28 +7,75 @@ import (
 
 type Repository interface {
 	CreatePR(ctx context.Context, pr *domain.PullRequest) error
-	SavePRReviewers(ctx context.Context, prID string, reviewerIDs []string) error
+	SavePRReviewers(ctx context.Context, prID string, version int, reviewerIDs []string) error
+	SavePRReviewerChange(ctx context.Context, prID string, version int, reviewerIDs []string, change domain.ReviewerChange) error
 	GetPRByID(ctx context.Context, prID string) (*domain.PullRequest, error)
-	UpdatePRStatus(ctx context.Context, prID, status string) error
+	UpdatePRStatus(ctx context.Context, prID string, version int, status string) error
 	GetPRsForReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
-	UpdatePRName(ctx context.Context, prID, name string) error
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	UpdatePRName(ctx context.Context, prID string, version int, name string) error
 	DeletePR(ctx context.Context, prID string) error
 }
 
+type CodeRepoRepository interface {
+	GetRepositoryByName(ctx context.Context, name string) (*domain.CodeRepository, error)
+	GetRepositoryByID(ctx context.Context, id string) (*domain.CodeRepository, error)
+}
+
 type TeamRepository interface {
-	GetTeamByName(ctx context.Context, userID string) (*domain.Team, error)
+	GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error)
+	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
 }
 
 type UserRepository interface {
 	GetUserByID(ctx context.Context, userID string) (*domain.User, error)
 }
 
+type Metrics interface {
+	PRCreated(reviewers int)
+	ReviewerReassigned()
+	PRMerged()
+	NoCandidate()
+	ReviewerDropped()
+}
+
+type ReviewerSync interface {
+	ReviewersChanged(ctx context.Context, pr *domain.PullRequest, added, removed []string)
+}
+
+type Notifier interface {
+	ReviewersAssigned(ctx context.Context, pr *domain.PullRequest, reviewers []string)
+	ReviewerReassigned(ctx context.Context, pr *domain.PullRequest, oldUserID, newUserID string)
+	PRMerged(ctx context.Context, pr *domain.PullRequest)
+}
+
+type CreateInput struct {
+	PRID       string
+	Name       string
+	AuthorID   string
+	TargetTeam string
+	Repository string
+	External   *domain.ExternalRef
+}
+
+type ReviewerChangeInput struct {
+	PRID            string
+	UserID          string
+	Force           bool
+	ActorTokenID    string
+	ExpectedVersion int
+}
+
 type Service interface {
-	CreatePRWithAssignments(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, *domain.PullRequest, error)
-	MergePR(ctx context.Context, prID string) (*domain.PullRequest, error)
+	CreatePRWithAssignments(ctx context.Context, in CreateInput) (*domain.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, expectedVersion int) (string, *domain.PullRequest, error)
+	HandOffReviews(ctx context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error)
+	AddReviewer(ctx context.Context, in ReviewerChangeInput) (*domain.PullRequest, error)
+	RemoveReviewer(ctx context.Context, in ReviewerChangeInput) (*domain.PullRequest, error)
+	MergePR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error)
+	ClosePR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error)
+	ReopenPR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error)
 	GetPRsForReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
 	GetPR(ctx context.Context, prID string) (*domain.PullRequest, error)
-	UpdatePR(ctx context.Context, pr *domain.PullRequest) error
 	DeletePR(ctx context.Context, prID string) error
 }
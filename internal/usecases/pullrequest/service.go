@@ -2,77 +2,173 @@ package pullrequest
 
 import (
 	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 )
 
+const handoffAttempts = 3
+
 type service struct {
-	repo     Repository
-	teamRepo TeamRepository
-	userRepo UserRepository
-	limit    int
+	repo         Repository
+	teamRepo     TeamRepository
+	userRepo     UserRepository
+	codeRepoRepo CodeRepoRepository
+	metrics      Metrics
+	sync         ReviewerSync
+	notifier     Notifier
+	limit        int
+}
+
+type Option func(*service)
+
+func WithMetrics(m Metrics) Option {
+	return func(s *service) {
+		s.metrics = m
+	}
 }
 
-func NewService(r Repository, t TeamRepository, u UserRepository) Service {
-	return &service{repo: r, teamRepo: t, userRepo: u, limit: 2}
+func WithReviewerSync(rs ReviewerSync) Option {
+	return func(s *service) {
+		s.sync = rs
+	}
 }
 
-func (s *service) CreatePRWithAssignments(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
-	if _, err := uuid.Parse(prID); err != nil {
+func WithNotifier(n Notifier) Option {
+	return func(s *service) {
+		s.notifier = n
+	}
+}
+
+func NewService(r Repository, t TeamRepository, u UserRepository, c CodeRepoRepository, opts ...Option) Service {
+	s := &service{repo: r, teamRepo: t, userRepo: u, codeRepoRepo: c, metrics: noopMetrics{}, sync: noopSync{}, notifier: noopNotifier{}, limit: 2}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *service) CreatePRWithAssignments(ctx context.Context, in CreateInput) (*domain.PullRequest, error) {
+	if _, err := uuid.Parse(in.PRID); err != nil {
 		return nil, fmt.Errorf("invalid pr id: %w", err)
 	}
-	if _, err := uuid.Parse(authorID); err != nil {
+	if _, err := uuid.Parse(in.AuthorID); err != nil {
 		return nil, fmt.Errorf("invalid author id: %w", err)
 	}
-	user, err := s.userRepo.GetUserByID(ctx, authorID)
+	user, err := s.userRepo.GetUserByID(ctx, in.AuthorID)
 	if err != nil {
 		return nil, err
 	}
-	team, err := s.teamRepo.GetTeamByName(ctx, *user.TeamName)
+	policy := domain.ReviewerPolicy{ReviewerCount: s.limit, Strategy: domain.StrategyFirstAvailable}
+	var repo *domain.CodeRepository
+	if in.Repository != "" {
+		repo, err = s.codeRepoRepo.GetRepositoryByName(ctx, in.Repository)
+		if err != nil {
+			return nil, err
+		}
+		policy = repo.Policy
+	}
+	var team *domain.Team
+	switch {
+	case in.TargetTeam != "":
+		team, err = s.teamRepo.GetTeamByName(ctx, in.TargetTeam)
+	case repo != nil && repo.OwningTeamID != nil:
+		team, err = s.teamRepo.GetTeamByID(ctx, *repo.OwningTeamID)
+	case user.TeamID != nil:
+		team, err = s.teamRepo.GetTeamByID(ctx, *user.TeamID)
+	default:
+		return nil, domain.ErrNoTeam
+	}
 	if err != nil {
 		return nil, err
 	}
-	members := team.Members
 	pr := &domain.PullRequest{
-		ID:       prID,
-		Name:     prName,
-		AuthorID: authorID,
+		ID:       in.PRID,
+		Name:     in.Name,
+		AuthorID: in.AuthorID,
+		TeamID:   team.ID,
 		Status:   domain.StatusOpen,
+		External: in.External,
+	}
+	if repo != nil {
+		pr.RepositoryID = repo.ID
+	}
+	var load map[string]int
+	if policy.Strategy == domain.StrategyLeastLoaded {
+		ids := make([]string, 0, len(team.Members))
+		for _, m := range team.Members {
+			ids = append(ids, m.UserID)
+		}
+		if load, err = s.repo.CountOpenReviews(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+	pr.AssignReviewers(team.Members, policy, load)
+	if err := pr.Validate(team, policy.ReviewerCount, pr.AssignedReviewers...); err != nil {
+		return nil, err
 	}
-	pr.AssignReviewersFromMembers(members, s.limit)
 	if err := s.repo.CreatePR(ctx, pr); err != nil {
 		return nil, err
 	}
+	s.metrics.PRCreated(len(pr.AssignedReviewers))
+	s.sync.ReviewersChanged(ctx, pr, pr.AssignedReviewers, nil)
 	if len(pr.AssignedReviewers) > 0 {
-		if err := s.repo.SavePRReviewers(ctx, pr.ID, pr.AssignedReviewers); err != nil {
-			return nil, err
-		}
+		s.notifier.ReviewersAssigned(ctx, pr, pr.AssignedReviewers)
 	}
+	logging.FromContext(ctx).Info("pr created", "pr_id", pr.ID, "author_id", pr.AuthorID, "team_id", pr.TeamID, "repository_id", pr.RepositoryID, "reviewers", pr.AssignedReviewers)
 	return pr, nil
 }
 
-func (s *service) ReassignReviewer(ctx context.Context, prID, oldUserID string) (string, *domain.PullRequest, error) {
+func (s *service) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, expectedVersion int) (string, *domain.PullRequest, error) {
 	pr, err := s.repo.GetPRByID(ctx, prID)
 	if err != nil {
 		return "", nil, err
 	}
+	if err := pr.CheckVersion(expectedVersion); err != nil {
+		return "", nil, err
+	}
 	if pr.Status == domain.StatusMerged {
 		return "", nil, domain.ErrPRMerged
 	}
-	oldUser, err := s.userRepo.GetUserByID(ctx, oldUserID)
+	if pr.Status == domain.StatusClosed {
+		return "", nil, domain.ErrPRClosed
+	}
+	team, err := s.prTeam(ctx, pr)
 	if err != nil {
 		return "", nil, err
 	}
-	team, err := s.teamRepo.GetTeamByName(ctx, *oldUser.TeamName)
-	if err != nil {
+	candidate := newUserID
+	if candidate == "" {
+		if candidate = pickCandidate(pr, team, oldUserID); candidate == "" {
+			s.metrics.NoCandidate()
+			logging.FromContext(ctx).Warn("no reassignment candidate", "pr_id", pr.ID, "old_reviewer_id", oldUserID)
+			return "", nil, domain.ErrNoCandidate
+		}
+	}
+	if err := pr.Reassign(oldUserID, candidate); err != nil {
+		return "", nil, err
+	}
+	if err := pr.Validate(team, domain.NoReviewerLimit, candidate); err != nil {
+		return "", nil, err
+	}
+	if err := s.repo.SavePRReviewers(ctx, pr.ID, pr.Version, pr.AssignedReviewers); err != nil {
 		return "", nil, err
 	}
-	members := team.Members
-	var candidate string
-	for _, member := range members {
-		if !member.IsActive || member.UserID == oldUserID {
+	pr.Version++
+	s.metrics.ReviewerReassigned()
+	s.sync.ReviewersChanged(ctx, pr, []string{candidate}, []string{oldUserID})
+	s.notifier.ReviewerReassigned(ctx, pr, oldUserID, candidate)
+	logging.FromContext(ctx).Info("reviewer reassigned", "pr_id", pr.ID, "old_reviewer_id", oldUserID, "new_reviewer_id", candidate)
+	return candidate, pr, nil
+}
+
+func pickCandidate(pr *domain.PullRequest, team *domain.Team, oldUserID string) string {
+	for _, member := range team.Members {
+		if !member.CanReview() || member.UserID == oldUserID || member.UserID == pr.AuthorID {
 			continue
 		}
 		already := false
@@ -83,23 +179,220 @@ func (s *service) ReassignReviewer(ctx context.Context, prID, oldUserID string)
 			}
 		}
 		if !already {
-			candidate = member.UserID
-			break
+			return member.UserID
 		}
 	}
-	if candidate == "" {
-		return "", nil, domain.ErrNoCandidate
+	return ""
+}
+
+func (s *service) HandOffReviews(ctx context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error) {
+	for attempt := 1; ; attempt++ {
+		handoffs, err := s.planHandoffs(ctx, userIDs, teamID)
+		if err != nil {
+			return nil, err
+		}
+		err = write(handoffs)
+		if errors.Is(err, domain.ErrVersionConflict) && attempt < handoffAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.reviewersHandedOff(ctx, handoffs)
+		return handoffs, nil
 	}
-	if err := pr.Reassign(oldUserID, candidate); err != nil {
-		return "", nil, err
+}
+
+func (s *service) planHandoffs(ctx context.Context, userIDs []string, teamID *string) ([]domain.ReviewHandoff, error) {
+	var out []domain.ReviewHandoff
+	planned := make(map[string]bool)
+	for _, userID := range userIDs {
+		prs, err := s.repo.GetPRsForReviewer(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			pr := &prs[i]
+			if pr.Status != domain.StatusOpen || (teamID != nil && pr.TeamID != *teamID) || planned[pr.ID] {
+				continue
+			}
+			planned[pr.ID] = true
+			handoffs, err := s.planPR(ctx, pr, userIDs)
+			if err != nil {
+				return nil, fmt.Errorf("reassign reviews of pr %s: %w", pr.ID, err)
+			}
+			out = append(out, handoffs...)
+		}
 	}
-	if err := s.repo.SavePRReviewers(ctx, pr.ID, pr.AssignedReviewers); err != nil {
-		return "", nil, err
+	return out, nil
+}
+
+func (s *service) planPR(ctx context.Context, pr *domain.PullRequest, leaving []string) ([]domain.ReviewHandoff, error) {
+	team, err := s.prTeam(ctx, pr)
+	if err != nil && !errors.Is(err, domain.ErrTeamNotFound) && !errors.Is(err, domain.ErrNoTeam) {
+		return nil, err
+	}
+	if team != nil {
+		stay := *team
+		stay.Members = slices.DeleteFunc(slices.Clone(team.Members), func(m domain.TeamMember) bool { return slices.Contains(leaving, m.UserID) })
+		team = &stay
+	}
+	var out []domain.ReviewHandoff
+	var added []string
+	for _, userID := range leaving {
+		if !slices.Contains(pr.AssignedReviewers, userID) {
+			continue
+		}
+		h := domain.ReviewHandoff{PRID: pr.ID, PRName: pr.Name, OldReviewerID: userID, Version: pr.Version}
+		if team != nil {
+			h.NewReviewerID = pickCandidate(pr, team, userID)
+		}
+		if h.NewReviewerID != "" {
+			err = pr.Reassign(userID, h.NewReviewerID)
+			added = append(added, h.NewReviewerID)
+		} else {
+			err = pr.RemoveReviewer(userID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	if err := pr.Validate(team, domain.NoReviewerLimit, added...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *service) reviewersHandedOff(ctx context.Context, handoffs []domain.ReviewHandoff) {
+	byPR := make(map[string][]domain.ReviewHandoff)
+	var order []string
+	for _, h := range handoffs {
+		if _, ok := byPR[h.PRID]; !ok {
+			order = append(order, h.PRID)
+		}
+		byPR[h.PRID] = append(byPR[h.PRID], h)
+	}
+	for _, prID := range order {
+		log := logging.FromContext(ctx).With("pr_id", prID)
+		pr, err := s.repo.GetPRByID(ctx, prID)
+		if err != nil {
+			log.Warn("load pr after review handoff", "err", err)
+			continue
+		}
+		var added, removed []string
+		for _, h := range byPR[prID] {
+			removed = append(removed, h.OldReviewerID)
+			if h.NewReviewerID == "" {
+				s.metrics.ReviewerDropped()
+				log.Info("reviewer removed without replacement", "reviewer_id", h.OldReviewerID)
+				continue
+			}
+			added = append(added, h.NewReviewerID)
+			s.metrics.ReviewerReassigned()
+			s.notifier.ReviewerReassigned(ctx, pr, h.OldReviewerID, h.NewReviewerID)
+			log.Info("reviewer reassigned", "old_reviewer_id", h.OldReviewerID, "new_reviewer_id", h.NewReviewerID)
+		}
+		s.sync.ReviewersChanged(ctx, pr, added, removed)
 	}
-	return candidate, pr, nil
 }
 
-func (s *service) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+func (s *service) AddReviewer(ctx context.Context, in ReviewerChangeInput) (*domain.PullRequest, error) {
+	pr, err := s.repo.GetPRByID(ctx, in.PRID)
+	if err != nil {
+		return nil, err
+	}
+	if err := pr.CheckVersion(in.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	if pr.Status == domain.StatusMerged {
+		return nil, domain.ErrPRMerged
+	}
+	if pr.Status == domain.StatusClosed {
+		return nil, domain.ErrPRClosed
+	}
+	team, err := s.prTeam(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	limit := domain.NoReviewerLimit
+	if !in.Force {
+		policy, err := s.prPolicy(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+		limit = policy.ReviewerCount
+	}
+	if err := pr.AddReviewer(in.UserID); err != nil {
+		return nil, err
+	}
+	if err := pr.Validate(team, limit, in.UserID); err != nil {
+		return nil, err
+	}
+	change := domain.ReviewerChange{PRID: pr.ID, UserID: in.UserID, Action: domain.ReviewerAdded, Forced: in.Force, ActorTokenID: in.ActorTokenID}
+	if err := s.repo.SavePRReviewerChange(ctx, pr.ID, pr.Version, pr.AssignedReviewers, change); err != nil {
+		return nil, err
+	}
+	pr.Version++
+	s.sync.ReviewersChanged(ctx, pr, []string{in.UserID}, nil)
+	s.notifier.ReviewersAssigned(ctx, pr, []string{in.UserID})
+	logging.FromContext(ctx).Info("reviewer added", "pr_id", pr.ID, "reviewer_id", in.UserID, "forced", in.Force)
+	return pr, nil
+}
+
+func (s *service) RemoveReviewer(ctx context.Context, in ReviewerChangeInput) (*domain.PullRequest, error) {
+	pr, err := s.repo.GetPRByID(ctx, in.PRID)
+	if err != nil {
+		return nil, err
+	}
+	if err := pr.CheckVersion(in.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	if err := pr.RemoveReviewer(in.UserID); err != nil {
+		return nil, err
+	}
+	if err := pr.Validate(nil, domain.NoReviewerLimit); err != nil {
+		return nil, err
+	}
+	change := domain.ReviewerChange{PRID: pr.ID, UserID: in.UserID, Action: domain.ReviewerRemoved, ActorTokenID: in.ActorTokenID}
+	if err := s.repo.SavePRReviewerChange(ctx, pr.ID, pr.Version, pr.AssignedReviewers, change); err != nil {
+		return nil, err
+	}
+	pr.Version++
+	s.sync.ReviewersChanged(ctx, pr, nil, []string{in.UserID})
+	logging.FromContext(ctx).Info("reviewer removed", "pr_id", pr.ID, "reviewer_id", in.UserID)
+	return pr, nil
+}
+
+func (s *service) prPolicy(ctx context.Context, pr *domain.PullRequest) (domain.ReviewerPolicy, error) {
+	if pr.RepositoryID == "" {
+		return domain.ReviewerPolicy{ReviewerCount: s.limit, Strategy: domain.StrategyFirstAvailable}, nil
+	}
+	repo, err := s.codeRepoRepo.GetRepositoryByID(ctx, pr.RepositoryID)
+	if err != nil {
+		return domain.ReviewerPolicy{}, err
+	}
+	return repo.Policy, nil
+}
+
+func (s *service) prTeam(ctx context.Context, pr *domain.PullRequest) (*domain.Team, error) {
+	if pr.TeamID != "" {
+		return s.teamRepo.GetTeamByID(ctx, pr.TeamID)
+	}
+	if pr.AuthorID == "" {
+		return nil, domain.ErrNoTeam
+	}
+	author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author.TeamID == nil {
+		return nil, domain.ErrNoTeam
+	}
+	return s.teamRepo.GetTeamByID(ctx, *author.TeamID)
+}
+
+func (s *service) MergePR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error) {
 	pr, err := s.repo.GetPRByID(ctx, prID)
 	if err != nil {
 		return nil, err
@@ -107,10 +400,50 @@ func (s *service) MergePR(ctx context.Context, prID string) (*domain.PullRequest
 	if pr.Status == domain.StatusMerged {
 		return pr, nil
 	}
+	if pr.Status == domain.StatusClosed {
+		return nil, domain.ErrPRClosed
+	}
+	if err := pr.CheckVersion(expectedVersion); err != nil {
+		return nil, err
+	}
 	pr.Merge()
-	if err := s.repo.UpdatePRStatus(ctx, pr.ID, string(pr.Status)); err != nil {
+	if err := s.repo.UpdatePRStatus(ctx, pr.ID, pr.Version, string(pr.Status)); err != nil {
+		return nil, err
+	}
+	pr.Version++
+	s.metrics.PRMerged()
+	s.notifier.PRMerged(ctx, pr)
+	logging.FromContext(ctx).Info("pr merged", "pr_id", pr.ID)
+	return pr, nil
+}
+
+func (s *service) ClosePR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, expectedVersion, domain.StatusClosed, (*domain.PullRequest).Close)
+}
+
+func (s *service) ReopenPR(ctx context.Context, prID string, expectedVersion int) (*domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, expectedVersion, domain.StatusOpen, (*domain.PullRequest).Reopen)
+}
+
+func (s *service) changeStatus(ctx context.Context, prID string, expectedVersion int, target domain.PRStatus, apply func(*domain.PullRequest) error) (*domain.PullRequest, error) {
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == target {
+		return pr, nil
+	}
+	if err := pr.CheckVersion(expectedVersion); err != nil {
+		return nil, err
+	}
+	if err := apply(pr); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePRStatus(ctx, pr.ID, pr.Version, string(pr.Status)); err != nil {
 		return nil, err
 	}
+	pr.Version++
+	logging.FromContext(ctx).Info("pr status changed", "pr_id", pr.ID, "status", pr.Status)
 	return pr, nil
 }
 
@@ -122,13 +455,24 @@ func (s *service) GetPR(ctx context.Context, prID string) (*domain.PullRequest,
 	return s.repo.GetPRByID(ctx, prID)
 }
 
-func (s *service) UpdatePR(ctx context.Context, pr *domain.PullRequest) error {
-	if err := s.repo.UpdatePRStatus(ctx, pr.ID, string(pr.Status)); err != nil {
-		return err
-	}
-	return s.repo.SavePRReviewers(ctx, pr.ID, pr.AssignedReviewers)
-}
-
 func (s *service) DeletePR(ctx context.Context, prID string) error {
 	return s.repo.DeletePR(ctx, prID)
 }
+
+type noopMetrics struct{}
+
+func (noopMetrics) PRCreated(int)       {}
+func (noopMetrics) ReviewerReassigned() {}
+func (noopMetrics) PRMerged()           {}
+func (noopMetrics) NoCandidate()        {}
+func (noopMetrics) ReviewerDropped()    {}
+
+type noopSync struct{}
+
+func (noopSync) ReviewersChanged(context.Context, *domain.PullRequest, []string, []string) {}
+
+type noopNotifier struct{}
+
+func (noopNotifier) ReviewersAssigned(context.Context, *domain.PullRequest, []string)        {}
+func (noopNotifier) ReviewerReassigned(context.Context, *domain.PullRequest, string, string) {}
+func (noopNotifier) PRMerged(context.Context, *domain.PullRequest)                           {}
@@ -0,0 +1,156 @@
+package pullrequest
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type memPRs struct {
+	Repository
+	prs map[string]domain.PullRequest
+}
+
+func (m *memPRs) GetPRByID(_ context.Context, prID string) (*domain.PullRequest, error) {
+	pr, ok := m.prs[prID]
+	if !ok {
+		return nil, domain.ErrPRNotFound
+	}
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	return &pr, nil
+}
+
+func (m *memPRs) GetPRsForReviewer(_ context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	var out []domain.PullRequest
+	for _, pr := range m.prs {
+		if slices.Contains(pr.AssignedReviewers, reviewerID) {
+			pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+			out = append(out, pr)
+		}
+	}
+	return out, nil
+}
+
+type memTeams map[string]*domain.Team
+
+func (t memTeams) GetTeamByID(_ context.Context, id string) (*domain.Team, error) {
+	team, ok := t[id]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+func (t memTeams) GetTeamByName(context.Context, string) (*domain.Team, error) {
+	return nil, domain.ErrTeamNotFound
+}
+
+type recordingNotifier struct {
+	noopNotifier
+	reassigned [][2]string
+}
+
+func (n *recordingNotifier) ReviewerReassigned(_ context.Context, _ *domain.PullRequest, oldUserID, newUserID string) {
+	n.reassigned = append(n.reassigned, [2]string{oldUserID, newUserID})
+}
+
+type countingMetrics struct {
+	noopMetrics
+	noCandidate, dropped int
+}
+
+func (m *countingMetrics) NoCandidate()     { m.noCandidate++ }
+func (m *countingMetrics) ReviewerDropped() { m.dropped++ }
+
+func member(id string) domain.TeamMember {
+	return domain.TeamMember{UserID: id, IsActive: true, MembershipActive: true, Role: domain.MemberRoleMember}
+}
+
+func newHandoffService(opts ...Option) (Service, *memPRs, *recordingNotifier) {
+	prs := &memPRs{prs: map[string]domain.PullRequest{
+		"pr-1":   {ID: "pr-1", AuthorID: "author", TeamID: "team-1", Status: domain.StatusOpen, Version: 4, AssignedReviewers: []string{"u1", "u2"}},
+		"merged": {ID: "merged", AuthorID: "author", TeamID: "team-1", Status: domain.StatusMerged, AssignedReviewers: []string{"u1"}},
+		"other":  {ID: "other", AuthorID: "author", TeamID: "team-2", Status: domain.StatusOpen, AssignedReviewers: []string{"u1"}},
+	}}
+	teams := memTeams{
+		"team-1": {ID: "team-1", Members: []domain.TeamMember{member("author"), member("u1"), member("u2"), member("u3")}},
+		"team-2": {ID: "team-2", Members: []domain.TeamMember{member("author"), member("u1"), member("u3")}},
+	}
+	notifier := &recordingNotifier{}
+	return NewService(prs, teams, nil, nil, append(opts, WithNotifier(notifier))...), prs, notifier
+}
+
+func TestHandOffReviewsWritesPlanOnce(t *testing.T) {
+	svc, _, notifier := newHandoffService()
+	team := "team-1"
+	var written []domain.ReviewHandoff
+	out, err := svc.HandOffReviews(context.Background(), []string{"u1", "u2"}, &team, func(hs []domain.ReviewHandoff) error {
+		if len(notifier.reassigned) != 0 {
+			t.Error("notified before the write committed")
+		}
+		written = hs
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ReviewHandoff{
+		{PRID: "pr-1", OldReviewerID: "u1", NewReviewerID: "u3", Version: 4},
+		{PRID: "pr-1", OldReviewerID: "u2", Version: 4},
+	}
+	if !slices.Equal(written, want) || !slices.Equal(out, want) {
+		t.Fatalf("handoffs = %+v, want %+v", written, want)
+	}
+	if len(notifier.reassigned) != 1 || notifier.reassigned[0] != [2]string{"u1", "u3"} {
+		t.Errorf("notifications = %v", notifier.reassigned)
+	}
+}
+
+func TestHandOffReviewsCountsDroppedReviewersSeparately(t *testing.T) {
+	metrics := &countingMetrics{}
+	svc, _, _ := newHandoffService(WithMetrics(metrics))
+	team := "team-1"
+	if _, err := svc.HandOffReviews(context.Background(), []string{"u1", "u2"}, &team, func([]domain.ReviewHandoff) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.dropped != 1 || metrics.noCandidate != 0 {
+		t.Errorf("dropped = %d, no candidate = %d", metrics.dropped, metrics.noCandidate)
+	}
+}
+
+func TestHandOffReviewsReplansOnConflict(t *testing.T) {
+	svc, prs, notifier := newHandoffService()
+	calls := 0
+	out, err := svc.HandOffReviews(context.Background(), []string{"u2"}, nil, func(hs []domain.ReviewHandoff) error {
+		calls++
+		if calls == 1 {
+			pr := prs.prs["pr-1"]
+			pr.Version++
+			prs.prs["pr-1"] = pr
+			return domain.ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || len(out) != 1 || out[0].Version != 5 || out[0].NewReviewerID != "u3" {
+		t.Fatalf("calls = %d, handoffs = %+v", calls, out)
+	}
+	if len(notifier.reassigned) != 1 {
+		t.Errorf("notifications = %v", notifier.reassigned)
+	}
+}
+
+func TestHandOffReviewsWriteFailureHasNoSideEffects(t *testing.T) {
+	svc, _, notifier := newHandoffService()
+	boom := errors.New("boom")
+	if _, err := svc.HandOffReviews(context.Background(), []string{"u1"}, nil, func([]domain.ReviewHandoff) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v", err)
+	}
+	if len(notifier.reassigned) != 0 {
+		t.Errorf("notified after a failed write: %v", notifier.reassigned)
+	}
+}
@@ -0,0 +1,113 @@
+package pullrequest
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/pullrequest")}
+}
+
+func (s *tracedService) CreatePRWithAssignments(ctx context.Context, in CreateInput) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.CreatePRWithAssignments", trace.WithAttributes(
+		attribute.String("pr.id", in.PRID),
+		attribute.String("pr.author_id", in.AuthorID),
+		attribute.String("pr.target_team", in.TargetTeam),
+		attribute.String("pr.repository", in.Repository),
+	))
+	defer func() { tracing.End(span, err) }()
+	pr, err = s.next.CreatePRWithAssignments(ctx, in)
+	if err == nil {
+		span.SetAttributes(attribute.Int("pr.reviewers", len(pr.AssignedReviewers)))
+	}
+	return pr, err
+}
+
+func (s *tracedService) ReassignReviewer(ctx context.Context, prID, oldUserID, newUserID string, expectedVersion int) (newID string, pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.ReassignReviewer", trace.WithAttributes(
+		attribute.String("pr.id", prID),
+		attribute.String("pr.old_reviewer_id", oldUserID),
+		attribute.Bool("pr.explicit_reviewer", newUserID != ""),
+	))
+	defer func() { tracing.End(span, err) }()
+	newID, pr, err = s.next.ReassignReviewer(ctx, prID, oldUserID, newUserID, expectedVersion)
+	if err == nil {
+		span.SetAttributes(attribute.String("pr.new_reviewer_id", newID))
+	}
+	return newID, pr, err
+}
+
+func (s *tracedService) HandOffReviews(ctx context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) (out []domain.ReviewHandoff, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.HandOffReviews", trace.WithAttributes(
+		attribute.StringSlice("pr.old_reviewer_ids", userIDs),
+	))
+	defer func() { tracing.End(span, err) }()
+	out, err = s.next.HandOffReviews(ctx, userIDs, teamID, write)
+	span.SetAttributes(attribute.Int("pr.handoffs", len(out)))
+	return out, err
+}
+
+func (s *tracedService) AddReviewer(ctx context.Context, in ReviewerChangeInput) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.AddReviewer", trace.WithAttributes(
+		attribute.String("pr.id", in.PRID),
+		attribute.String("pr.reviewer_id", in.UserID),
+		attribute.Bool("pr.force", in.Force),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.next.AddReviewer(ctx, in)
+}
+
+func (s *tracedService) RemoveReviewer(ctx context.Context, in ReviewerChangeInput) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.RemoveReviewer", trace.WithAttributes(
+		attribute.String("pr.id", in.PRID),
+		attribute.String("pr.reviewer_id", in.UserID),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RemoveReviewer(ctx, in)
+}
+
+func (s *tracedService) MergePR(ctx context.Context, prID string, expectedVersion int) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.MergePR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.MergePR(ctx, prID, expectedVersion)
+}
+
+func (s *tracedService) ClosePR(ctx context.Context, prID string, expectedVersion int) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.ClosePR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ClosePR(ctx, prID, expectedVersion)
+}
+
+func (s *tracedService) ReopenPR(ctx context.Context, prID string, expectedVersion int) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.ReopenPR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ReopenPR(ctx, prID, expectedVersion)
+}
+
+func (s *tracedService) GetPRsForReviewer(ctx context.Context, reviewerID string) (prs []domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.GetPRsForReviewer", trace.WithAttributes(attribute.String("reviewer.id", reviewerID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetPRsForReviewer(ctx, reviewerID)
+}
+
+func (s *tracedService) GetPR(ctx context.Context, prID string) (pr *domain.PullRequest, err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.GetPR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetPR(ctx, prID)
+}
+
+func (s *tracedService) DeletePR(ctx context.Context, prID string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "pullrequest.DeletePR", trace.WithAttributes(attribute.String("pr.id", prID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeletePR(ctx, prID)
+}
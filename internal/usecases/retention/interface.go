@@ -0,0 +1,18 @@
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+type UserRepository interface {
+	PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error)
+}
+
+type TeamRepository interface {
+	PurgeDeletedTeams(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Service interface {
+	Purge(ctx context.Context) error
+}
@@ -0,0 +1,48 @@
+package retention
+
+import (
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"time"
+)
+
+type service struct {
+	users  UserRepository
+	teams  TeamRepository
+	period time.Duration
+}
+
+func NewService(u UserRepository, t TeamRepository, period time.Duration) Service {
+	return &service{users: u, teams: t, period: period}
+}
+
+func (s *service) Purge(ctx context.Context) error {
+	before := time.Now().Add(-s.period)
+	users, err := s.users.PurgeDeletedUsers(ctx, before)
+	if err != nil {
+		return err
+	}
+	teams, err := s.teams.PurgeDeletedTeams(ctx, before)
+	if err != nil {
+		return err
+	}
+	if users > 0 || teams > 0 {
+		logging.FromContext(ctx).Info("purged soft-deleted records", "users", users, "teams", teams, "deleted_before", before)
+	}
+	return nil
+}
+
+func Run(ctx context.Context, svc Service, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := svc.Purge(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("purge soft-deleted records", "err", err)
+			}
+		}
+	}
+}
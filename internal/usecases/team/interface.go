@@ -3,18 +3,39 @@ package team
 import (
 	"AvitoTestTask/internal/domain"
 	"context"
+	"time"
 )
 
 type Repository interface {
 	CreateTeam(ctx context.Context, teamName string) (string, error)
+	CreateTeamWithMembers(ctx context.Context, teamName string, members []string) (string, error)
 	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error)
+	ListTeams(ctx context.Context) ([]domain.Team, error)
 	UpdateTeam(ctx context.Context, oldName, newName string) error
-	DeleteTeam(ctx context.Context, teamName string) error
+	ReplaceTeam(ctx context.Context, teamID, teamName string, members []string, handoffs []domain.ReviewHandoff) error
+	DeleteTeam(ctx context.Context, teamName string, handoffs []domain.ReviewHandoff) error
+	RestoreTeam(ctx context.Context, teamName string) (string, error)
+	PurgeDeletedTeams(ctx context.Context, before time.Time) (int64, error)
+	UpsertMembership(ctx context.Context, teamID, userID string, role domain.MemberRole, active bool, handoffs []domain.ReviewHandoff) error
+	DeleteMembership(ctx context.Context, teamID, userID string, handoffs []domain.ReviewHandoff) error
+}
+
+type ReviewReassigner interface {
+	HandOffReviews(ctx context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error)
 }
 
 type Service interface {
 	CreateTeam(ctx context.Context, teamName string) (string, error)
+	CreateTeamWithMembers(ctx context.Context, teamName string, members []string) (string, error)
 	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error)
+	ListTeams(ctx context.Context) ([]domain.Team, error)
 	UpdateTeam(ctx context.Context, oldName, newName string) error
+	ReplaceTeam(ctx context.Context, teamID, teamName string, members []string) error
 	DeleteTeam(ctx context.Context, teamName string) error
+	RestoreTeam(ctx context.Context, teamName string) (*domain.Team, error)
+	AddMember(ctx context.Context, teamName, userID string, role domain.MemberRole, active bool) error
+	UpdateMember(ctx context.Context, teamName, userID string, role *domain.MemberRole, active *bool) error
+	RemoveMember(ctx context.Context, teamName, userID string) error
 }
@@ -2,29 +2,201 @@ package team
 
 import (
 	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
 	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
 )
 
 type service struct {
 	repository Repository
+	reviews    ReviewReassigner
+}
+
+type Option func(*service)
+
+func WithReviewReassigner(rr ReviewReassigner) Option {
+	return func(s *service) {
+		s.reviews = rr
+	}
 }
 
-func NewService(r Repository) Service {
-	return &service{repository: r}
+func NewService(r Repository, opts ...Option) Service {
+	s := &service{repository: r, reviews: noopReassigner{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) CreateTeam(ctx context.Context, teamName string) (string, error) {
 	return s.repository.CreateTeam(ctx, teamName)
 }
 
+func (s *service) CreateTeamWithMembers(ctx context.Context, teamName string, members []string) (string, error) {
+	if err := validMembers(members); err != nil {
+		return "", err
+	}
+	return s.repository.CreateTeamWithMembers(ctx, teamName, members)
+}
+
 func (s *service) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
 	return s.repository.GetTeamByName(ctx, teamName)
 }
 
+func (s *service) GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error) {
+	return s.repository.GetTeamByID(ctx, teamID)
+}
+
+func (s *service) ListTeams(ctx context.Context) ([]domain.Team, error) {
+	return s.repository.ListTeams(ctx)
+}
+
 func (s *service) UpdateTeam(ctx context.Context, oldName, NewName string) error {
 	return s.repository.UpdateTeam(ctx, oldName, NewName)
 }
 
+func (s *service) ReplaceTeam(ctx context.Context, teamID, teamName string, members []string) error {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return domain.ErrInvalidID
+	}
+	if err := validMembers(members); err != nil {
+		return err
+	}
+	team, err := s.repository.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	var removed []string
+	for _, m := range team.Members {
+		if !slices.Contains(members, m.UserID) {
+			removed = append(removed, m.UserID)
+		}
+	}
+	_, err = s.handOff(ctx, removed, team.ID, func(handoffs []domain.ReviewHandoff) error {
+		return s.repository.ReplaceTeam(ctx, teamID, teamName, members, handoffs)
+	})
+	return err
+}
+
 func (s *service) DeleteTeam(ctx context.Context, teamName string) error {
-	return s.repository.DeleteTeam(ctx, teamName)
+	team, err := s.repository.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	members := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, m.UserID)
+	}
+	if _, err := s.handOff(ctx, members, team.ID, func(handoffs []domain.ReviewHandoff) error {
+		return s.repository.DeleteTeam(ctx, teamName, handoffs)
+	}); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("team deleted", "team_name", teamName)
+	return nil
+}
+
+func (s *service) RestoreTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	id, err := s.repository.RestoreTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("team restored", "team_id", id, "team_name", teamName)
+	return s.repository.GetTeamByID(ctx, id)
+}
+
+func (s *service) AddMember(ctx context.Context, teamName, userID string, role domain.MemberRole, active bool) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return errors.New("invalid user_id")
+	}
+	if role == "" {
+		role = domain.MemberRoleMember
+	}
+	if !role.Valid() {
+		return errors.New("invalid role")
+	}
+	team, err := s.repository.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	return s.saveMembership(ctx, team, userID, role, active)
+}
+
+func (s *service) UpdateMember(ctx context.Context, teamName, userID string, role *domain.MemberRole, active *bool) error {
+	if role != nil && !role.Valid() {
+		return errors.New("invalid role")
+	}
+	team, err := s.repository.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	for _, m := range team.Members {
+		if m.UserID != userID {
+			continue
+		}
+		if role != nil {
+			m.Role = *role
+		}
+		if active != nil {
+			m.MembershipActive = *active
+		}
+		return s.saveMembership(ctx, team, userID, m.Role, m.MembershipActive)
+	}
+	return domain.ErrNotMember
+}
+
+func (s *service) RemoveMember(ctx context.Context, teamName, userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return errors.New("invalid user_id")
+	}
+	team, err := s.repository.GetTeamByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	_, err = s.handOff(ctx, []string{userID}, team.ID, func(handoffs []domain.ReviewHandoff) error {
+		return s.repository.DeleteMembership(ctx, team.ID, userID, handoffs)
+	})
+	return err
+}
+
+func (s *service) saveMembership(ctx context.Context, team *domain.Team, userID string, role domain.MemberRole, active bool) error {
+	var leaving []string
+	if !active {
+		if m, ok := team.Member(userID); ok && m.MembershipActive {
+			leaving = []string{userID}
+		}
+	}
+	_, err := s.handOff(ctx, leaving, team.ID, func(handoffs []domain.ReviewHandoff) error {
+		return s.repository.UpsertMembership(ctx, team.ID, userID, role, active, handoffs)
+	})
+	return err
+}
+
+func (s *service) handOff(ctx context.Context, userIDs []string, teamID string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error) {
+	if len(userIDs) == 0 {
+		return nil, write(nil)
+	}
+	handoffs, err := s.reviews.HandOffReviews(ctx, userIDs, &teamID, write)
+	if err == nil && len(handoffs) > 0 {
+		logging.FromContext(ctx).Info("team reviews handed off", "team_id", teamID, "users", userIDs, "reassigned_reviews", len(handoffs))
+	}
+	return handoffs, err
+}
+
+func validMembers(members []string) error {
+	for _, uid := range members {
+		if _, err := uuid.Parse(uid); err != nil {
+			return errors.New("member " + uid + ": invalid user_id")
+		}
+	}
+	return nil
+}
+
+type noopReassigner struct{}
+
+func (noopReassigner) HandOffReviews(_ context.Context, _ []string, _ *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error) {
+	return nil, write(nil)
 }
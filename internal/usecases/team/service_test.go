@@ -0,0 +1,52 @@
+package team
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"slices"
+	"testing"
+)
+
+type memTeams struct {
+	Repository
+	team     *domain.Team
+	deleted  bool
+	handoffs []domain.ReviewHandoff
+}
+
+func (r *memTeams) GetTeamByName(_ context.Context, name string) (*domain.Team, error) {
+	if r.team == nil || r.team.TeamName != name || r.deleted {
+		return nil, domain.ErrTeamNotFound
+	}
+	return r.team, nil
+}
+
+func (r *memTeams) DeleteTeam(_ context.Context, _ string, handoffs []domain.ReviewHandoff) error {
+	r.deleted, r.handoffs = true, handoffs
+	return nil
+}
+
+type recordingReassigner struct {
+	userIDs []string
+	teamID  string
+}
+
+func (r *recordingReassigner) HandOffReviews(_ context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error) {
+	r.userIDs, r.teamID = userIDs, *teamID
+	handoffs := []domain.ReviewHandoff{{PRID: "pr-1", OldReviewerID: userIDs[0]}}
+	return handoffs, write(handoffs)
+}
+
+func TestDeleteTeamHandsOffMemberReviews(t *testing.T) {
+	repo := &memTeams{team: &domain.Team{ID: "team-1", TeamName: "backend", Members: []domain.TeamMember{{UserID: "u1"}, {UserID: "u2"}}}}
+	reviews := &recordingReassigner{}
+	if err := NewService(repo, WithReviewReassigner(reviews)).DeleteTeam(context.Background(), "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if reviews.teamID != "team-1" || !slices.Equal(reviews.userIDs, []string{"u1", "u2"}) {
+		t.Fatalf("handed off %v in %q", reviews.userIDs, reviews.teamID)
+	}
+	if !repo.deleted || len(repo.handoffs) != 1 {
+		t.Fatalf("deleted = %v, handoffs written with the delete = %v", repo.deleted, repo.handoffs)
+	}
+}
@@ -0,0 +1,100 @@
+package team
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/team")}
+}
+
+func (s *tracedService) CreateTeam(ctx context.Context, teamName string) (id string, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.CreateTeam", trace.WithAttributes(attribute.String("team.name", teamName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateTeam(ctx, teamName)
+}
+
+func (s *tracedService) CreateTeamWithMembers(ctx context.Context, teamName string, members []string) (id string, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.CreateTeamWithMembers", trace.WithAttributes(attribute.String("team.name", teamName), attribute.Int("team.members", len(members))))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateTeamWithMembers(ctx, teamName, members)
+}
+
+func (s *tracedService) GetTeamByName(ctx context.Context, teamName string) (t *domain.Team, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.GetTeamByName", trace.WithAttributes(attribute.String("team.name", teamName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTeamByName(ctx, teamName)
+}
+
+func (s *tracedService) GetTeamByID(ctx context.Context, teamID string) (t *domain.Team, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.GetTeamByID", trace.WithAttributes(attribute.String("team.id", teamID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTeamByID(ctx, teamID)
+}
+
+func (s *tracedService) ListTeams(ctx context.Context) (out []domain.Team, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.ListTeams")
+	defer func() { tracing.End(span, err) }()
+	return s.next.ListTeams(ctx)
+}
+
+func (s *tracedService) UpdateTeam(ctx context.Context, oldName, newName string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.UpdateTeam", trace.WithAttributes(attribute.String("team.name", oldName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateTeam(ctx, oldName, newName)
+}
+
+func (s *tracedService) ReplaceTeam(ctx context.Context, teamID, teamName string, members []string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.ReplaceTeam", trace.WithAttributes(attribute.String("team.id", teamID), attribute.Int("team.members", len(members))))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ReplaceTeam(ctx, teamID, teamName, members)
+}
+
+func (s *tracedService) DeleteTeam(ctx context.Context, teamName string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.DeleteTeam", trace.WithAttributes(attribute.String("team.name", teamName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteTeam(ctx, teamName)
+}
+
+func (s *tracedService) AddMember(ctx context.Context, teamName, userID string, role domain.MemberRole, active bool) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.AddMember", trace.WithAttributes(
+		attribute.String("team.name", teamName),
+		attribute.String("user.id", userID),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.next.AddMember(ctx, teamName, userID, role, active)
+}
+
+func (s *tracedService) UpdateMember(ctx context.Context, teamName, userID string, role *domain.MemberRole, active *bool) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.UpdateMember", trace.WithAttributes(
+		attribute.String("team.name", teamName),
+		attribute.String("user.id", userID),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateMember(ctx, teamName, userID, role, active)
+}
+
+func (s *tracedService) RemoveMember(ctx context.Context, teamName, userID string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "team.RemoveMember", trace.WithAttributes(
+		attribute.String("team.name", teamName),
+		attribute.String("user.id", userID),
+	))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RemoveMember(ctx, teamName, userID)
+}
+
+func (s *tracedService) RestoreTeam(ctx context.Context, teamName string) (t *domain.Team, err error) {
+	ctx, span := s.tracer.Start(ctx, "team.RestoreTeam", trace.WithAttributes(attribute.String("team.name", teamName)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RestoreTeam(ctx, teamName)
+}
@@ -3,19 +3,35 @@ package user
 import (
 	"AvitoTestTask/internal/domain"
 	"context"
+	"time"
 )
 
 type Repository interface {
 	CreateUser(ctx context.Context, u domain.User) error
 	GetUserByID(ctx context.Context, userID string) (*domain.User, error)
-	UpdateUser(ctx context.Context, u domain.User) error
-	DeleteUser(ctx context.Context, userID string) error
+	ListUsers(ctx context.Context, username string) ([]domain.User, error)
+	UpdateUser(ctx context.Context, u domain.User, handoffs []domain.ReviewHandoff) error
+	DeleteUser(ctx context.Context, userID string, handoffs []domain.ReviewHandoff) error
+	RestoreUser(ctx context.Context, userID string) error
+	PurgeDeletedUsers(ctx context.Context, before time.Time) (int64, error)
 	SetUserTeamByName(ctx context.Context, userID string, teamName *string) error
+	SetUserTeamByID(ctx context.Context, userID string, teamID *string) error
+}
+
+type TeamRepository interface {
+	GetTeamByID(ctx context.Context, teamID string) (*domain.Team, error)
+	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type ReviewReassigner interface {
+	HandOffReviews(ctx context.Context, userIDs []string, teamID *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error)
 }
 
 type Service interface {
 	CreateUser(ctx context.Context, u domain.User) error
 	GetUser(ctx context.Context, userID string) (*domain.User, error)
-	UpdateUser(ctx context.Context, u domain.User) error
-	DeleteUser(ctx context.Context, userID string) error
+	ListUsers(ctx context.Context, username string) ([]domain.User, error)
+	UpdateUser(ctx context.Context, u domain.User) ([]domain.ReviewHandoff, error)
+	DeleteUser(ctx context.Context, userID string) ([]domain.ReviewHandoff, error)
+	RestoreUser(ctx context.Context, userID string) error
 }
@@ -2,23 +2,42 @@ package user
 
 import (
 	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
 	"context"
 	"errors"
+
 	"github.com/google/uuid"
 )
 
 type service struct {
 	repository Repository
+	teamRepo   TeamRepository
+	reviews    ReviewReassigner
 }
 
-func NewService(r Repository) Service {
-	return &service{repository: r}
+type Option func(*service)
+
+func WithReviewReassigner(rr ReviewReassigner) Option {
+	return func(s *service) {
+		s.reviews = rr
+	}
+}
+
+func NewService(r Repository, t TeamRepository, opts ...Option) Service {
+	s := &service{repository: r, teamRepo: t, reviews: noopReassigner{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) CreateUser(ctx context.Context, u domain.User) error {
 	if _, err := uuid.Parse(u.ID); err != nil {
 		return errors.New("invalid user_id")
 	}
+	if err := s.resolveTeam(ctx, &u); err != nil {
+		return err
+	}
 	return s.repository.CreateUser(ctx, u)
 }
 
@@ -29,16 +48,85 @@ func (s *service) GetUser(ctx context.Context, userID string) (*domain.User, err
 	return s.repository.GetUserByID(ctx, userID)
 }
 
-func (s *service) UpdateUser(ctx context.Context, u domain.User) error {
+func (s *service) ListUsers(ctx context.Context, username string) ([]domain.User, error) {
+	return s.repository.ListUsers(ctx, username)
+}
+
+func (s *service) UpdateUser(ctx context.Context, u domain.User) ([]domain.ReviewHandoff, error) {
 	if _, err := uuid.Parse(u.ID); err != nil {
-		return errors.New("invalid user_id")
+		return nil, errors.New("invalid user_id")
+	}
+	if err := s.resolveTeam(ctx, &u); err != nil {
+		return nil, err
+	}
+	existing, err := s.repository.GetUserByID(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	write := func(handoffs []domain.ReviewHandoff) error { return s.repository.UpdateUser(ctx, u, handoffs) }
+	switch {
+	case existing.IsActive && !u.IsActive:
+		return s.reviews.HandOffReviews(ctx, []string{u.ID}, nil, write)
+	case existing.TeamID != nil && (u.TeamID == nil || *u.TeamID != *existing.TeamID):
+		return s.reviews.HandOffReviews(ctx, []string{u.ID}, existing.TeamID, write)
 	}
-	return s.repository.UpdateUser(ctx, u)
+	return nil, write(nil)
 }
 
-func (s *service) DeleteUser(ctx context.Context, userID string) error {
+func (s *service) DeleteUser(ctx context.Context, userID string) ([]domain.ReviewHandoff, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user_id")
+	}
+	if _, err := s.repository.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	handoffs, err := s.reviews.HandOffReviews(ctx, []string{userID}, nil, func(handoffs []domain.ReviewHandoff) error {
+		return s.repository.DeleteUser(ctx, userID, handoffs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("user deleted", "user_id", userID, "reassigned_reviews", len(handoffs))
+	return handoffs, nil
+}
+
+func (s *service) RestoreUser(ctx context.Context, userID string) error {
 	if _, err := uuid.Parse(userID); err != nil {
 		return errors.New("invalid user_id")
 	}
-	return s.repository.DeleteUser(ctx, userID)
+	if err := s.repository.RestoreUser(ctx, userID); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("user restored", "user_id", userID)
+	return nil
+}
+
+func (s *service) resolveTeam(ctx context.Context, u *domain.User) error {
+	switch {
+	case u.TeamID != nil:
+		if _, err := uuid.Parse(*u.TeamID); err != nil {
+			return domain.ErrInvalidID
+		}
+		team, err := s.teamRepo.GetTeamByID(ctx, *u.TeamID)
+		if err != nil {
+			return err
+		}
+		if u.TeamName != nil && *u.TeamName != team.TeamName {
+			return domain.ErrTeamMismatch
+		}
+		u.TeamName = &team.TeamName
+	case u.TeamName != nil:
+		team, err := s.teamRepo.GetTeamByName(ctx, *u.TeamName)
+		if err != nil {
+			return err
+		}
+		u.TeamID = &team.ID
+	}
+	return nil
+}
+
+type noopReassigner struct{}
+
+func (noopReassigner) HandOffReviews(_ context.Context, _ []string, _ *string, write func([]domain.ReviewHandoff) error) ([]domain.ReviewHandoff, error) {
+	return nil, write(nil)
 }
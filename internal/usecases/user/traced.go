@@ -0,0 +1,59 @@
+package user
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/user")}
+}
+
+func (s *tracedService) CreateUser(ctx context.Context, u domain.User) (err error) {
+	ctx, span := s.tracer.Start(ctx, "user.CreateUser", trace.WithAttributes(attribute.String("user.id", u.ID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateUser(ctx, u)
+}
+
+func (s *tracedService) GetUser(ctx context.Context, userID string) (u *domain.User, err error) {
+	ctx, span := s.tracer.Start(ctx, "user.GetUser", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetUser(ctx, userID)
+}
+
+func (s *tracedService) ListUsers(ctx context.Context, username string) (out []domain.User, err error) {
+	ctx, span := s.tracer.Start(ctx, "user.ListUsers")
+	defer func() { tracing.End(span, err) }()
+	return s.next.ListUsers(ctx, username)
+}
+
+func (s *tracedService) UpdateUser(ctx context.Context, u domain.User) (out []domain.ReviewHandoff, err error) {
+	ctx, span := s.tracer.Start(ctx, "user.UpdateUser", trace.WithAttributes(attribute.String("user.id", u.ID)))
+	defer func() { tracing.End(span, err) }()
+	out, err = s.next.UpdateUser(ctx, u)
+	span.SetAttributes(attribute.Int("user.reassigned_reviews", len(out)))
+	return out, err
+}
+
+func (s *tracedService) DeleteUser(ctx context.Context, userID string) (out []domain.ReviewHandoff, err error) {
+	ctx, span := s.tracer.Start(ctx, "user.DeleteUser", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	out, err = s.next.DeleteUser(ctx, userID)
+	span.SetAttributes(attribute.Int("user.reassigned_reviews", len(out)))
+	return out, err
+}
+
+func (s *tracedService) RestoreUser(ctx context.Context, userID string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "user.RestoreUser", trace.WithAttributes(attribute.String("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RestoreUser(ctx, userID)
+}
@@ -0,0 +1,31 @@
+package vcsevent
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+)
+
+type IdentityResolver interface {
+	Resolve(ctx context.Context, provider, login string) (string, error)
+}
+
+type RepositoryLookup interface {
+	GetRepository(ctx context.Context, name string) (*domain.CodeRepository, error)
+}
+
+type Result struct {
+	Action string
+	PR     *domain.PullRequest
+}
+
+const (
+	ResultCreated  = "created"
+	ResultReopened = "reopened"
+	ResultClosed   = "closed"
+	ResultMerged   = "merged"
+	ResultIgnored  = "ignored"
+)
+
+type Service interface {
+	HandlePullRequestEvent(ctx context.Context, ev domain.PullRequestEvent) (*Result, error)
+}
@@ -0,0 +1,96 @@
+package vcsevent
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	pruc "AvitoTestTask/internal/usecases/pullrequest"
+)
+
+var prNamespace = uuid.MustParse("6f1c8e0a-3b7d-4d2e-9a51-2c4f0b8e7d13")
+
+type service struct {
+	prSvc      pruc.Service
+	identities IdentityResolver
+	repos      RepositoryLookup
+}
+
+func NewService(prSvc pruc.Service, identities IdentityResolver, repos RepositoryLookup) Service {
+	return &service{prSvc: prSvc, identities: identities, repos: repos}
+}
+
+func PRIDFor(ref domain.ExternalRef) string {
+	return uuid.NewSHA1(prNamespace, []byte(fmt.Sprintf("%s:%s#%d", ref.Provider, ref.Repo, ref.Number))).String()
+}
+
+func (s *service) HandlePullRequestEvent(ctx context.Context, ev domain.PullRequestEvent) (*Result, error) {
+	prID := PRIDFor(ev.Ref)
+	log := logging.FromContext(ctx).With("provider", ev.Ref.Provider, "repo", ev.Ref.Repo, "number", ev.Ref.Number, "pr_id", prID)
+	existing, err := s.prSvc.GetPR(ctx, prID)
+	if err != nil && !errors.Is(err, domain.ErrPRNotFound) {
+		return nil, err
+	}
+	switch ev.Action {
+	case domain.PREventOpened, domain.PREventReopened:
+		if existing != nil {
+			if existing.Status != domain.StatusClosed {
+				return &Result{Action: ResultIgnored, PR: existing}, nil
+			}
+			pr, err := s.prSvc.ReopenPR(ctx, prID, 0)
+			if err != nil {
+				return nil, err
+			}
+			log.Info("external pr reopened")
+			return &Result{Action: ResultReopened, PR: pr}, nil
+		}
+		if ev.Draft {
+			return &Result{Action: ResultIgnored}, nil
+		}
+		if ev.AuthorLogin == "" {
+			return nil, domain.ErrUnknownIdentity
+		}
+		authorID, err := s.identities.Resolve(ctx, ev.Ref.Provider, ev.AuthorLogin)
+		if err != nil {
+			return nil, err
+		}
+		ref := ev.Ref
+		in := pruc.CreateInput{PRID: prID, Name: ev.Title, AuthorID: authorID, External: &ref}
+		if _, err := s.repos.GetRepository(ctx, ev.Ref.Repo); err == nil {
+			in.Repository = ev.Ref.Repo
+		} else if !errors.Is(err, domain.ErrRepositoryNotFound) {
+			return nil, err
+		}
+		pr, err := s.prSvc.CreatePRWithAssignments(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("external pr created", "author_id", authorID)
+		return &Result{Action: ResultCreated, PR: pr}, nil
+	case domain.PREventClosed:
+		if existing == nil || existing.Status == domain.StatusMerged {
+			return &Result{Action: ResultIgnored}, nil
+		}
+		pr, err := s.prSvc.ClosePR(ctx, prID, 0)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("external pr closed")
+		return &Result{Action: ResultClosed, PR: pr}, nil
+	case domain.PREventMerged:
+		if existing == nil {
+			return &Result{Action: ResultIgnored}, nil
+		}
+		pr, err := s.prSvc.MergePR(ctx, prID, 0)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("external pr merged")
+		return &Result{Action: ResultMerged, PR: pr}, nil
+	}
+	return &Result{Action: ResultIgnored, PR: existing}, nil
+}
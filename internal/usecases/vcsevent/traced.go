@@ -0,0 +1,34 @@
+package vcsevent
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/vcsevent")}
+}
+
+func (s *tracedService) HandlePullRequestEvent(ctx context.Context, ev domain.PullRequestEvent) (out *Result, err error) {
+	ctx, span := s.tracer.Start(ctx, "vcsevent.HandlePullRequestEvent", trace.WithAttributes(
+		attribute.String("vcs.provider", ev.Ref.Provider),
+		attribute.String("vcs.repo", ev.Ref.Repo),
+		attribute.Int("vcs.number", ev.Ref.Number),
+		attribute.String("vcs.action", string(ev.Action)),
+	))
+	defer func() { tracing.End(span, err) }()
+	out, err = s.next.HandlePullRequestEvent(ctx, ev)
+	if out != nil {
+		span.SetAttributes(attribute.String("vcs.result", out.Action))
+	}
+	return out, err
+}
@@ -0,0 +1,29 @@
+package vcssync
+
+import (
+	"AvitoTestTask/internal/domain"
+	"context"
+	"time"
+)
+
+type VCSClient interface {
+	RequestReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error
+	RemoveReviewers(ctx context.Context, ref domain.ExternalRef, logins []string) error
+}
+
+type IdentityLookup interface {
+	LoginsFor(ctx context.Context, provider string, userIDs []string) (map[string]string, error)
+}
+
+type JobRepository interface {
+	EnqueueJob(ctx context.Context, job domain.VCSJob) error
+	ClaimDueJobs(ctx context.Context, limit int, lease time.Duration) ([]domain.VCSJob, error)
+	DeleteJob(ctx context.Context, id int64) error
+	RescheduleJob(ctx context.Context, id int64, attempts int, nextRunAt time.Time, lastError string) error
+	FailJob(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+type Service interface {
+	ReviewersChanged(ctx context.Context, pr *domain.PullRequest, added, removed []string)
+	ProcessDue(ctx context.Context) (int, error)
+}
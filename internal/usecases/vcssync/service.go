@@ -0,0 +1,121 @@
+package vcssync
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/logging"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	batchSize   = 50
+	lease       = 2 * time.Minute
+	maxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+type service struct {
+	clients    map[string]VCSClient
+	identities IdentityLookup
+	jobs       JobRepository
+}
+
+func NewService(clients map[string]VCSClient, identities IdentityLookup, jobs JobRepository) Service {
+	return &service{clients: clients, identities: identities, jobs: jobs}
+}
+
+func (s *service) ReviewersChanged(ctx context.Context, pr *domain.PullRequest, added, removed []string) {
+	if pr.External == nil || s.clients[pr.External.Provider] == nil {
+		return
+	}
+	log := logging.FromContext(ctx).With("pr_id", pr.ID, "provider", pr.External.Provider, "repo", pr.External.Repo, "number", pr.External.Number)
+	logins, err := s.identities.LoginsFor(ctx, pr.External.Provider, append(append([]string{}, added...), removed...))
+	if err != nil {
+		log.Error("resolve vcs logins", "err", err)
+		return
+	}
+	now := time.Now()
+	for _, job := range []domain.VCSJob{
+		{Ref: *pr.External, Action: domain.VCSRemoveReviewers, Logins: loginsOf(removed, logins), NextRunAt: now},
+		{Ref: *pr.External, Action: domain.VCSRequestReviewers, Logins: loginsOf(added, logins), NextRunAt: now},
+	} {
+		if len(job.Logins) == 0 {
+			continue
+		}
+		if err := s.jobs.EnqueueJob(ctx, job); err != nil {
+			log.Error("enqueue vcs job", "action", job.Action, "err", err)
+		}
+	}
+}
+
+func (s *service) ProcessDue(ctx context.Context) (int, error) {
+	jobs, err := s.jobs.ClaimDueJobs(ctx, batchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		log := logging.FromContext(ctx).With("job_id", job.ID, "action", job.Action, "repo", job.Ref.Repo, "number", job.Ref.Number)
+		runErr := s.run(ctx, job)
+		switch {
+		case runErr == nil:
+			err = s.jobs.DeleteJob(ctx, job.ID)
+			log.Info("vcs job done", "attempts", job.Attempts+1)
+		case job.Attempts+1 >= maxAttempts:
+			err = s.jobs.FailJob(ctx, job.ID, job.Attempts+1, runErr.Error())
+			log.Error("vcs job failed permanently", "attempts", job.Attempts+1, "err", runErr)
+		default:
+			err = s.jobs.RescheduleJob(ctx, job.ID, job.Attempts+1, time.Now().Add(backoff(job.Attempts+1)), runErr.Error())
+			log.Warn("vcs job failed", "attempts", job.Attempts+1, "err", runErr)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(jobs), nil
+}
+
+func (s *service) run(ctx context.Context, job domain.VCSJob) error {
+	client := s.clients[job.Ref.Provider]
+	if client == nil {
+		return fmt.Errorf("no vcs client configured for %s", job.Ref.Provider)
+	}
+	if job.Action == domain.VCSRemoveReviewers {
+		return client.RemoveReviewers(ctx, job.Ref, job.Logins)
+	}
+	return client.RequestReviewers(ctx, job.Ref, job.Logins)
+}
+
+func Run(ctx context.Context, svc Service, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := svc.ProcessDue(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("process vcs jobs", "err", err)
+			}
+		}
+	}
+}
+
+func loginsOf(userIDs []string, logins map[string]string) []string {
+	out := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if l, ok := logins[id]; ok {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
@@ -0,0 +1,217 @@
+package vcssync
+
+import (
+	"AvitoTestTask/internal/adapters/vcs"
+	"AvitoTestTask/internal/domain"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memJobs struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   []domain.VCSJob
+	failed []domain.VCSJob
+}
+
+func (m *memJobs) EnqueueJob(_ context.Context, job domain.VCSJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	job.ID = m.nextID
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+// ClaimDueJobs mirrors the postgres claim: only the oldest pending job of each PR is due.
+func (m *memJobs) ClaimDueJobs(_ context.Context, limit int, lease time.Duration) ([]domain.VCSJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	seen := map[domain.ExternalRef]bool{}
+	var out []domain.VCSJob
+	for i, j := range m.jobs {
+		if seen[j.Ref] {
+			continue
+		}
+		seen[j.Ref] = true
+		if j.NextRunAt.After(now) || len(out) == limit {
+			continue
+		}
+		m.jobs[i].NextRunAt = now.Add(lease)
+		out = append(out, j)
+	}
+	return out, nil
+}
+
+func (m *memJobs) DeleteJob(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs = slices.DeleteFunc(m.jobs, func(j domain.VCSJob) bool { return j.ID == id })
+	return nil
+}
+
+func (m *memJobs) RescheduleJob(_ context.Context, id int64, attempts int, nextRunAt time.Time, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.jobs {
+		if m.jobs[i].ID == id {
+			m.jobs[i].Attempts, m.jobs[i].NextRunAt, m.jobs[i].LastError = attempts, nextRunAt, lastError
+		}
+	}
+	return nil
+}
+
+func (m *memJobs) FailJob(_ context.Context, id int64, attempts int, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.IndexFunc(m.jobs, func(j domain.VCSJob) bool { return j.ID == id })
+	job := m.jobs[i]
+	job.Attempts, job.LastError = attempts, lastError
+	m.failed = append(m.failed, job)
+	m.jobs = slices.Delete(m.jobs, i, i+1)
+	return nil
+}
+
+func (m *memJobs) makeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.jobs {
+		m.jobs[i].NextRunAt = time.Time{}
+	}
+}
+
+type staticLogins map[string]string
+
+func (l staticLogins) LoginsFor(_ context.Context, _ string, userIDs []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, id := range userIDs {
+		if login, ok := l[id]; ok {
+			out[id] = login
+		}
+	}
+	return out, nil
+}
+
+var (
+	testRef    = domain.ExternalRef{Provider: domain.ProviderGitHub, Repo: "acme/widgets", Number: 42}
+	testLogins = staticLogins{"u1": "alice", "u2": "bob", "u3": "carol"}
+)
+
+func newTestService(client VCSClient) (Service, *memJobs) {
+	jobs := &memJobs{}
+	return NewService(map[string]VCSClient{domain.ProviderGitHub: client}, testLogins, jobs), jobs
+}
+
+func TestReviewersChangedOnlyEnqueues(t *testing.T) {
+	client := vcs.NewFakeClient()
+	svc, jobs := newTestService(client)
+	pr := &domain.PullRequest{ID: "pr-1", External: &testRef}
+
+	svc.ReviewersChanged(context.Background(), pr, []string{"u3"}, []string{"u1", "unlinked"})
+
+	if calls := client.Calls(); len(calls) != 0 {
+		t.Fatalf("upstream called inside the request: %v", calls)
+	}
+	if len(jobs.jobs) != 2 {
+		t.Fatalf("queued %d jobs, want 2", len(jobs.jobs))
+	}
+	if j := jobs.jobs[0]; j.Action != domain.VCSRemoveReviewers || !slices.Equal(j.Logins, []string{"alice"}) {
+		t.Errorf("first job = %+v, want removal of alice", j)
+	}
+	if j := jobs.jobs[1]; j.Action != domain.VCSRequestReviewers || !slices.Equal(j.Logins, []string{"carol"}) {
+		t.Errorf("second job = %+v, want request for carol", j)
+	}
+}
+
+func TestReviewersChangedSkipsPRsWithoutClient(t *testing.T) {
+	svc, jobs := newTestService(vcs.NewFakeClient())
+	svc.ReviewersChanged(context.Background(), &domain.PullRequest{ID: "local"}, []string{"u1"}, nil)
+	gitlab := domain.ExternalRef{Provider: domain.ProviderGitLab, Repo: "platform/billing", Number: 7}
+	svc.ReviewersChanged(context.Background(), &domain.PullRequest{ID: "gl", External: &gitlab}, []string{"u1"}, nil)
+	if len(jobs.jobs) != 0 {
+		t.Fatalf("queued jobs for PRs without a client: %v", jobs.jobs)
+	}
+}
+
+func TestProcessDueKeepsPerPROrder(t *testing.T) {
+	client := vcs.NewFakeClient()
+	svc, jobs := newTestService(client)
+	pr := &domain.PullRequest{ID: "pr-1", External: &testRef}
+	ctx := context.Background()
+
+	svc.ReviewersChanged(ctx, pr, []string{"u1"}, nil)
+	svc.ReviewersChanged(ctx, pr, nil, []string{"u1"})
+	for i := 0; i < 3 && len(jobs.jobs) > 0; i++ {
+		if _, err := svc.ProcessDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calls := client.Calls()
+	if len(calls) != 2 || calls[0].Action != domain.VCSRequestReviewers || calls[1].Action != domain.VCSRemoveReviewers {
+		t.Fatalf("calls = %+v, want request then remove", calls)
+	}
+	if len(jobs.jobs) != 0 {
+		t.Fatalf("jobs left in queue: %v", jobs.jobs)
+	}
+}
+
+func TestProcessDueRetriesBehindFailedJob(t *testing.T) {
+	client := vcs.NewFakeClient()
+	client.Err = errors.New("github unavailable")
+	svc, jobs := newTestService(client)
+	pr := &domain.PullRequest{ID: "pr-1", External: &testRef}
+	ctx := context.Background()
+
+	svc.ReviewersChanged(ctx, pr, []string{"u1"}, nil)
+	svc.ReviewersChanged(ctx, pr, nil, []string{"u1"})
+	if _, err := svc.ProcessDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := jobs.jobs[0]; got.Attempts != 1 || got.LastError == "" || !got.NextRunAt.After(time.Now()) {
+		t.Fatalf("failed job not rescheduled with backoff: %+v", got)
+	}
+
+	// The newer removal must not overtake the add that is waiting for its retry.
+	jobs.mu.Lock()
+	jobs.jobs[1].NextRunAt = time.Time{}
+	jobs.mu.Unlock()
+	client.Err = nil
+	if n, err := svc.ProcessDue(ctx); err != nil || n != 0 {
+		t.Fatalf("processed %d jobs while the head job was backing off (err %v)", n, err)
+	}
+
+	jobs.makeDue()
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ProcessDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	calls := client.Calls()
+	if len(calls) != 2 || calls[0].Action != domain.VCSRequestReviewers || calls[1].Action != domain.VCSRemoveReviewers {
+		t.Fatalf("calls = %+v, want request then remove", calls)
+	}
+}
+
+func TestProcessDueGivesUpAfterMaxAttempts(t *testing.T) {
+	client := vcs.NewFakeClient()
+	client.Err = errors.New("github unavailable")
+	svc, jobs := newTestService(client)
+	ctx := context.Background()
+
+	svc.ReviewersChanged(ctx, &domain.PullRequest{ID: "pr-1", External: &testRef}, []string{"u2"}, nil)
+	for i := 0; i < maxAttempts; i++ {
+		jobs.makeDue()
+		if _, err := svc.ProcessDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(jobs.jobs) != 0 || len(jobs.failed) != 1 || jobs.failed[0].Attempts != maxAttempts {
+		t.Fatalf("pending %v, failed %v", jobs.jobs, jobs.failed)
+	}
+}
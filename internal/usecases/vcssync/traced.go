@@ -0,0 +1,38 @@
+package vcssync
+
+import (
+	"AvitoTestTask/internal/domain"
+	"AvitoTestTask/internal/infra/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: tracing.Tracer("AvitoTestTask/usecases/vcssync")}
+}
+
+func (s *tracedService) ReviewersChanged(ctx context.Context, pr *domain.PullRequest, added, removed []string) {
+	ctx, span := s.tracer.Start(ctx, "vcssync.ReviewersChanged", trace.WithAttributes(
+		attribute.String("pr.id", pr.ID),
+		attribute.Int("reviewers.added", len(added)),
+		attribute.Int("reviewers.removed", len(removed)),
+	))
+	defer span.End()
+	s.next.ReviewersChanged(ctx, pr, added, removed)
+}
+
+func (s *tracedService) ProcessDue(ctx context.Context) (n int, err error) {
+	ctx, span := s.tracer.Start(ctx, "vcssync.ProcessDue")
+	defer func() {
+		span.SetAttributes(attribute.Int("jobs.processed", n))
+		tracing.End(span, err)
+	}()
+	return s.next.ProcessDue(ctx)
+}